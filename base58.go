@@ -0,0 +1,120 @@
+package didkey
+
+import (
+	"unicode/utf8"
+)
+
+const base58BTCAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58BTCIndex maps ASCII characters to their base58-btc digit value, or -1
+var base58BTCIndex = func() [256]int8 {
+	var index [256]int8
+	for i := range index {
+		index[i] = -1
+	}
+	for i := 0; i < len(base58BTCAlphabet); i++ {
+		index[base58BTCAlphabet[i]] = int8(i)
+	}
+	return index
+}()
+
+// decodeBase58BTC decodes a base58-btc string (without the multibase prefix).
+//
+// It performs a big-endian base conversion into a single buffer sized up front
+// from the input length, so the only allocation is the returned slice. Each
+// leading '1' encodes a leading zero byte.
+func decodeBase58BTC(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+
+	size := decodedBase58Size(len(s) - zeros)
+	buf := make([]byte, zeros+size)
+	value := buf[zeros:]
+
+	// high is the index of the most significant byte written so far
+	high := size - 1
+	for i := zeros; i < len(s); i++ {
+		digit := base58BTCIndex[s[i]]
+		if digit < 0 {
+			char, _ := utf8.DecodeRuneInString(s[i:])
+			return nil, ErrInvalidBase58CharacterWithContext(char)
+		}
+
+		// carry stays below 58*256 (57 + 58*255 before the shift), so int arithmetic
+		// cannot overflow, even where int is 32 bits
+		carry := int(digit)
+		j := size - 1
+		for ; j > high || carry != 0; j-- {
+			carry += 58 * int(value[j])
+			value[j] = byte(carry)
+			carry >>= 8
+		}
+		high = j
+	}
+
+	// Skip the unused high bytes of the value, keeping one zero byte per leading '1'
+	start := zeros
+	for start < len(buf) && buf[start] == 0 {
+		start++
+	}
+
+	return buf[start-zeros:], nil
+}
+
+// encodeBase58BTC encodes data as base58-btc (without the multibase prefix).
+// Like decodeBase58BTC it converts into a single buffer sized from the input
+// length, and it cannot fail.
+func encodeBase58BTC(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	size := encodedBase58Size(len(data) - zeros)
+	digits := make([]byte, size)
+
+	// high is the index of the most significant digit written so far
+	high := size - 1
+	for _, b := range data[zeros:] {
+		// carry stays below 58*256 (255 + 256*57 before the division)
+		carry := int(b)
+		j := size - 1
+		for ; j > high || carry != 0; j-- {
+			carry += 256 * int(digits[j])
+			digits[j] = byte(carry % 58)
+			carry /= 58
+		}
+		high = j
+	}
+
+	start := 0
+	for start < size && digits[start] == 0 {
+		start++
+	}
+
+	out := make([]byte, zeros+size-start)
+	for i := 0; i < zeros; i++ {
+		out[i] = '1'
+	}
+	for i, d := range digits[start:] {
+		out[zeros+i] = base58BTCAlphabet[d]
+	}
+
+	return string(out)
+}
+
+// decodedBase58Size returns an upper bound on the bytes decoded from n base58
+// digits, each carrying log(58)/log(256) ≈ 0.733 bytes. The ratio is applied per
+// thousand digits so that n*733 cannot overflow a 32-bit int for long inputs.
+func decodedBase58Size(n int) int {
+	return n/1000*733 + n%1000*733/1000 + 1
+}
+
+// encodedBase58Size returns an upper bound on the base58 digits encoding n
+// bytes, each needing log(256)/log(58) ≈ 1.366 digits. As in decodedBase58Size,
+// the ratio is applied per thousand bytes to avoid overflow.
+func encodedBase58Size(n int) int {
+	return n/1000*1366 + n%1000*1366/1000 + 1
+}
@@ -0,0 +1,150 @@
+package didkey
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/mr-tron/base58"
+)
+
+func TestDecodeBase58BTC(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	inputs := [][]byte{
+		{},
+		{0},
+		{0, 0, 0},
+		{0, 0, 1},
+		{0xff},
+		bytes.Repeat([]byte{0xff}, 98),
+	}
+	for i := 0; i < 500; i++ {
+		input := make([]byte, rng.Intn(100))
+		rng.Read(input)
+		// Exercise leading zero handling
+		for j := 0; j < len(input) && rng.Intn(4) == 0; j++ {
+			input[j] = 0
+		}
+		inputs = append(inputs, input)
+	}
+
+	for _, input := range inputs {
+		encoded := base58.Encode(input)
+
+		decoded, err := decodeBase58BTC(encoded)
+		if err != nil {
+			t.Fatalf("Unexpected error decoding %q: %v", encoded, err)
+		}
+
+		if !bytes.Equal(decoded, input) {
+			t.Fatalf("Decoding %q: expected %x, got %x", encoded, input, decoded)
+		}
+	}
+
+	for _, invalid := range []string{"0", "O", "I", "l", "abc+", "z6Mk\x00"} {
+		if _, err := decodeBase58BTC(invalid); err == nil {
+			t.Errorf("Expected error decoding %q", invalid)
+		}
+	}
+}
+
+func BenchmarkDecodeBase58BLSG2(b *testing.B) {
+	keyBytes := make([]byte, 96)
+	keyBytes[0] = 0xa0
+	didKey, err := Encode(Bls12381G2PublicKey, keyBytes)
+	if err != nil {
+		b.Fatalf("Encode failed: %v", err)
+	}
+	encoded := didKey[len(DIDKeyPrefix)+1:]
+
+	b.Run("internal", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := decodeBase58BTC(encoded); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("mr-tron", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := base58.Decode(encoded); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestEncodeBase58BTC(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	inputs := [][]byte{
+		{},
+		{0},
+		{0, 0, 0},
+		{0, 0, 1},
+		{0xff},
+		bytes.Repeat([]byte{0xff}, 98),
+	}
+	for i := 0; i < 500; i++ {
+		input := make([]byte, rng.Intn(100))
+		rng.Read(input)
+		for j := 0; j < len(input) && rng.Intn(4) == 0; j++ {
+			input[j] = 0
+		}
+		inputs = append(inputs, input)
+	}
+
+	for _, input := range inputs {
+		expected := base58.Encode(input)
+		if encoded := encodeBase58BTC(input); encoded != expected {
+			t.Fatalf("Encoding %x: expected %q, got %q", input, expected, encoded)
+		}
+	}
+}
+
+func TestBase58BTCMaxValues(t *testing.T) {
+	// All-0xff bytes and all-'z' digits maximize every carry
+	for _, n := range []int{1, 2, 31, 32, 33, 48, 96, 500, 1000, 1001, 4096} {
+		data := bytes.Repeat([]byte{0xff}, n)
+		if encoded, expected := encodeBase58BTC(data), base58.Encode(data); encoded != expected {
+			t.Fatalf("Encoding %d 0xff bytes: expected %q, got %q", n, expected, encoded)
+		}
+
+		digits := strings.Repeat("z", n)
+		expected, err := base58.Decode(digits)
+		if err != nil {
+			t.Fatalf("Oracle failed to decode %d 'z' digits: %v", n, err)
+		}
+
+		decoded, err := decodeBase58BTC(digits)
+		if err != nil {
+			t.Fatalf("Unexpected error decoding %d 'z' digits: %v", n, err)
+		}
+
+		if !bytes.Equal(decoded, expected) {
+			t.Fatalf("Decoding %d 'z' digits: expected %x, got %x", n, expected, decoded)
+		}
+	}
+}
+
+func TestBase58BTCSizeOverflow(t *testing.T) {
+	// Lengths whose naive n*733 and n*1366 products overflow 32-bit ints
+	for _, n := range []int{0, 1, 999, 1000, 1001, 3_000_000, math.MaxInt32 / 2, math.MaxInt32} {
+		if size, ratio := decodedBase58Size(n), float64(n)*0.733; size <= 0 || float64(size) < ratio {
+			t.Errorf("decodedBase58Size(%d) = %d, expected at least %.0f", n, size, ratio)
+		}
+
+		// Only lengths that fit in memory on 32-bit platforms need to stay in range
+		if n > math.MaxInt32/2 {
+			continue
+		}
+		if size, ratio := encodedBase58Size(n), float64(n)*1.3657; size <= 0 || float64(size) < ratio {
+			t.Errorf("encodedBase58Size(%d) = %d, expected at least %.0f", n, size, ratio)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package didkey
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Default input limits of DecodeAllFrom, see WithInputLimits. A line fits the
+// longest DID key with plenty of room for surrounding whitespace.
+const (
+	DefaultMaxInputBytes = 1 << 20
+	DefaultMaxLineLength = 256
+)
+
+// KeyInput pairs a key type with raw key bytes for batch encoding
+type KeyInput struct {
+	KeyType  KeyType
+	KeyBytes []byte
+}
+
+// EncodeAll encodes a batch of keys to DID key strings, in order. By default it
+// stops at the first invalid key; with WithContinueOnError it encodes every key,
+// leaving an empty string for each failed key and joining the per-key errors.
+func EncodeAll(keys []KeyInput, opts ...Option) ([]string, error) {
+	o := applyOptions(opts)
+	didKeys := make([]string, len(keys))
+
+	var errs []error
+	for i, key := range keys {
+		didKey, err := Encode(key.KeyType, key.KeyBytes, opts...)
+		if err != nil {
+			errs = append(errs, batchItemError(i, err))
+			if !o.continueOnError {
+				return nil, errors.Join(errs...)
+			}
+			continue
+		}
+		didKeys[i] = didKey
+	}
+
+	return didKeys, errors.Join(errs...)
+}
+
+// DecodeAll decodes a batch of DID key strings, in order. By default it stops
+// at the first invalid DID key; with WithContinueOnError it decodes every DID
+// key, leaving nil for each failed one and joining the per-item errors.
+func DecodeAll(didKeys []string, opts ...Option) ([]*DIDKey, error) {
+	o := applyOptions(opts)
+	keys := make([]*DIDKey, len(didKeys))
+
+	var errs []error
+	for i, didKey := range didKeys {
+		key, err := Parse(didKey, opts...)
+		if err != nil {
+			errs = append(errs, batchItemError(i, err))
+			if !o.continueOnError {
+				return nil, errors.Join(errs...)
+			}
+			continue
+		}
+		keys[i] = key
+	}
+
+	return keys, errors.Join(errs...)
+}
+
+// DecodeJSONArray decodes a JSON array of DID key strings, such as an
+// allow-list in a config file. Every entry is decoded; failed entries are
+// left nil and their errors are joined, tagged with the array index.
+func DecodeJSONArray(data []byte, opts ...Option) ([]*DIDKey, error) {
+	var didKeys []string
+	if err := json.Unmarshal(data, &didKeys); err != nil {
+		return nil, ErrInvalidJSONArrayWithContext(err)
+	}
+
+	return DecodeAll(didKeys, append(slices.Clip(opts), WithContinueOnError())...)
+}
+
+// DecodeAllFrom decodes newline-separated DID key strings read from r, such as
+// an allow-list file, as DecodeAll does. Surrounding whitespace is trimmed and
+// blank lines are skipped; items are indexed by their position among the
+// non-blank lines.
+//
+// The input is bounded so untrusted streams cannot exhaust memory: more than
+// DefaultMaxInputBytes in total returns ErrInputTooLarge and a line longer
+// than DefaultMaxLineLength returns ErrLineTooLong, whatever
+// WithContinueOnError says. WithInputLimits changes the limits.
+func DecodeAllFrom(r io.Reader, opts ...Option) ([]*DIDKey, error) {
+	o := applyOptions(opts)
+	maxBytes, maxLineLength := o.maxInputBytes, o.maxLineLength
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxInputBytes
+	}
+	if maxLineLength <= 0 {
+		maxLineLength = DefaultMaxLineLength
+	}
+
+	// One byte over the limit tells an input of exactly maxBytes from a longer one
+	limited := &io.LimitedReader{R: r, N: maxBytes + 1}
+	scanner := bufio.NewScanner(limited)
+	// The buffer also holds the line ending: "\r\n" at most
+	scanner.Buffer(nil, maxLineLength+2)
+
+	var didKeys []string
+	line := 0
+	for scanner.Scan() {
+		line++
+		if limited.N == 0 {
+			return nil, ErrInputTooLargeWithContext(maxBytes)
+		}
+
+		// Line endings, including a "\r" before the "\n", are already dropped
+		text := scanner.Text()
+		if len(text) > maxLineLength {
+			return nil, ErrLineTooLongWithContext(line, maxLineLength)
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			didKeys = append(didKeys, text)
+		}
+	}
+
+	if limited.N == 0 {
+		return nil, ErrInputTooLargeWithContext(maxBytes)
+	}
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return nil, ErrLineTooLongWithContext(line+1, maxLineLength)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return DecodeAll(didKeys, opts...)
+}
+
+func batchItemError(index int, err error) error {
+	return fmt.Errorf("item %d: %w", index, err)
+}
+
+// GroupByType decodes a batch of DID key strings and groups them by key type,
+// keeping the input order within each group. Invalid DID keys do not stop the
+// grouping; their errors are returned separately, tagged with the item index.
+func GroupByType(didKeys []string, opts ...Option) (map[KeyType][]string, []error) {
+	groups := make(map[KeyType][]string)
+
+	var errs []error
+	for i, didKey := range didKeys {
+		keyType, _, err := Decode(didKey, opts...)
+		if err != nil {
+			errs = append(errs, batchItemError(i, err))
+			continue
+		}
+		groups[keyType] = append(groups[keyType], didKey)
+	}
+
+	return groups, errs
+}
@@ -0,0 +1,255 @@
+package didkey
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEncodeAll(t *testing.T) {
+	ed25519 := testVectors["Ed25519-from-spec"]
+	secp256k1 := testVectors["Secp256k1-test"]
+
+	keys := []KeyInput{
+		{KeyType: ed25519.keyType, KeyBytes: mustDecodeHex(ed25519.keyHex)},
+		{KeyType: Ed25519PublicKey, KeyBytes: make([]byte, 33)},
+		{KeyType: secp256k1.keyType, KeyBytes: mustDecodeHex(secp256k1.keyHex)},
+		{KeyType: P256PublicKey, KeyBytes: make([]byte, 40)},
+	}
+
+	t.Run("all valid", func(t *testing.T) {
+		didKeys, err := EncodeAll([]KeyInput{keys[0], keys[2]})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []string{ed25519.didKey, secp256k1.didKey}
+		if strings.Join(didKeys, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected %v, got %v", expected, didKeys)
+		}
+	})
+
+	t.Run("stops at first error", func(t *testing.T) {
+		didKeys, err := EncodeAll(keys)
+		if !errors.Is(err, ErrInvalidKeySize) {
+			t.Fatalf("Expected ErrInvalidKeySize, got %v", err)
+		}
+
+		if didKeys != nil {
+			t.Errorf("Expected no results, got %v", didKeys)
+		}
+
+		if !strings.Contains(err.Error(), "item 1") || strings.Contains(err.Error(), "item 3") {
+			t.Errorf("Expected only item 1 to be reported, got %q", err.Error())
+		}
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		didKeys, err := EncodeAll(keys, WithContinueOnError())
+		if !errors.Is(err, ErrInvalidKeySize) {
+			t.Fatalf("Expected ErrInvalidKeySize, got %v", err)
+		}
+
+		if !strings.Contains(err.Error(), "item 1") || !strings.Contains(err.Error(), "item 3") {
+			t.Errorf("Expected items 1 and 3 to be reported, got %q", err.Error())
+		}
+
+		expected := []string{ed25519.didKey, "", secp256k1.didKey, ""}
+		if strings.Join(didKeys, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected %v, got %v", expected, didKeys)
+		}
+	})
+}
+
+func TestDecodeAll(t *testing.T) {
+	didKeys := []string{
+		testVectors["Ed25519-from-spec"].didKey,
+		"did:web:example.com",
+		testVectors["P-256-test"].didKey,
+	}
+
+	t.Run("stops at first error", func(t *testing.T) {
+		keys, err := DecodeAll(didKeys)
+		if !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+			t.Fatalf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+		}
+
+		if keys != nil {
+			t.Errorf("Expected no results, got %v", keys)
+		}
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		keys, err := DecodeAll(didKeys, WithContinueOnError())
+		if !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+			t.Fatalf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+		}
+
+		if len(keys) != 3 || keys[1] != nil {
+			t.Fatalf("Expected a nil entry for the failed item, got %v", keys)
+		}
+
+		if keys[0].String() != didKeys[0] || keys[2].String() != didKeys[2] {
+			t.Errorf("Unexpected decoded keys %v", keys)
+		}
+	})
+}
+
+func TestGroupByType(t *testing.T) {
+	ed25519A := testVectors["Ed25519-from-spec"].didKey
+	ed25519B := testVectors["Ed25519-test-1"].didKey
+	p256 := testVectors["P-256-test"].didKey
+
+	groups, errs := GroupByType([]string{ed25519A, p256, "did:key:invalid", ed25519B})
+
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %v", groups)
+	}
+
+	if expected := []string{ed25519A, ed25519B}; !slices.Equal(groups[Ed25519PublicKey], expected) {
+		t.Errorf("Expected Ed25519 group %v, got %v", expected, groups[Ed25519PublicKey])
+	}
+
+	if expected := []string{p256}; !slices.Equal(groups[P256PublicKey], expected) {
+		t.Errorf("Expected P-256 group %v, got %v", expected, groups[P256PublicKey])
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %v", errs)
+	}
+
+	if !strings.HasPrefix(errs[0].Error(), "item 2: ") {
+		t.Errorf("Expected error for item 2, got %v", errs[0])
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		groups, errs := GroupByType(nil)
+		if len(groups) != 0 || errs != nil {
+			t.Errorf("Expected no groups or errors, got %v, %v", groups, errs)
+		}
+	})
+}
+
+func TestDecodeJSONArray(t *testing.T) {
+	ed25519 := testVectors["Ed25519-from-spec"]
+	secp256k1 := testVectors["Secp256k1-test"]
+
+	t.Run("valid array", func(t *testing.T) {
+		keys, err := DecodeJSONArray([]byte(`["` + ed25519.didKey + `", "` + secp256k1.didKey + `"]`))
+		if err != nil {
+			t.Fatalf("DecodeJSONArray failed: %v", err)
+		}
+		if len(keys) != 2 || keys[0].String() != ed25519.didKey || keys[1].String() != secp256k1.didKey {
+			t.Errorf("Unexpected keys: %v", keys)
+		}
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		keys, err := DecodeJSONArray([]byte(`["` + ed25519.didKey + `", "did:key:invalid", "` + secp256k1.didKey + `"]`))
+		if !errors.Is(err, ErrInvalidFingerprintLength) {
+			t.Fatalf("Expected ErrInvalidFingerprintLength, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "item 1:") {
+			t.Errorf("Expected error to report index 1, got %q", err.Error())
+		}
+		if len(keys) != 3 || keys[0] == nil || keys[1] != nil || keys[2] == nil {
+			t.Errorf("Expected only the invalid entry to be nil, got %v", keys)
+		}
+	})
+
+	t.Run("empty array", func(t *testing.T) {
+		keys, err := DecodeJSONArray([]byte(`[]`))
+		if err != nil || len(keys) != 0 {
+			t.Errorf("Expected no keys, got %v, %v", keys, err)
+		}
+	})
+
+	for _, data := range []string{`"` + ed25519.didKey + `"`, `[1, 2]`, `[`, `{}`} {
+		t.Run("malformed "+data, func(t *testing.T) {
+			if _, err := DecodeJSONArray([]byte(data)); !errors.Is(err, ErrInvalidJSONArray) {
+				t.Errorf("Expected ErrInvalidJSONArray, got %v", err)
+			}
+		})
+	}
+}
+
+// endlessReader is an unbounded stream of DID key lines, counting the bytes read
+type endlessReader struct {
+	line []byte
+	read int64
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r.line[r.read%int64(len(r.line))]
+		r.read++
+	}
+	return len(p), nil
+}
+
+func TestDecodeAllFrom(t *testing.T) {
+	ed25519 := testVectors["Ed25519-from-spec"].didKey
+	p256 := testVectors["P-256-test"].didKey
+
+	t.Run("lines", func(t *testing.T) {
+		input := ed25519 + "\r\n\n  " + p256 + "  \n"
+		keys, err := DecodeAllFrom(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("DecodeAllFrom failed: %v", err)
+		}
+
+		var got []string
+		for _, key := range keys {
+			got = append(got, key.String())
+		}
+		if expected := []string{ed25519, p256}; !slices.Equal(got, expected) {
+			t.Errorf("Expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("invalid item", func(t *testing.T) {
+		_, err := DecodeAllFrom(strings.NewReader(ed25519 + "\ndid:web:example.com\n"))
+		if !errors.Is(err, ErrInvalidDIDKeyPrefix) || !strings.Contains(err.Error(), "item 1") {
+			t.Errorf("Expected ErrInvalidDIDKeyPrefix for item 1, got %v", err)
+		}
+	})
+
+	t.Run("input exactly at the limit", func(t *testing.T) {
+		input := ed25519 + "\n" + p256
+		if _, err := DecodeAllFrom(strings.NewReader(input), WithInputLimits(int64(len(input)), 0)); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("input too large", func(t *testing.T) {
+		r := &endlessReader{line: []byte(ed25519 + "\n")}
+		_, err := DecodeAllFrom(r, WithInputLimits(4096, 0), WithContinueOnError())
+		if !errors.Is(err, ErrInputTooLarge) {
+			t.Fatalf("Expected ErrInputTooLarge, got %v", err)
+		}
+		if r.read > 4096+1 {
+			t.Errorf("Expected at most 4097 bytes to be read, got %d", r.read)
+		}
+	})
+
+	t.Run("line too long", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			input string
+		}{
+			{name: "just over", input: ed25519 + "\n" + ed25519 + "x\n"},
+			{name: "far over", input: ed25519 + "\n" + strings.Repeat("x", 1000) + "\n"},
+			{name: "no line ending", input: ed25519 + "\n" + strings.Repeat("x", 1000)},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := DecodeAllFrom(strings.NewReader(tt.input), WithInputLimits(0, len(ed25519)))
+				if !errors.Is(err, ErrLineTooLong) || !strings.Contains(err.Error(), "line 2") {
+					t.Errorf("Expected ErrLineTooLong for line 2, got %v", err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,85 @@
+//go:build bls
+
+package didkey
+
+import (
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// blsVerification reports whether BLS verification is compiled in
+const blsVerification = true
+
+// VerifyBLS verifies a BLS signature of message by a BLS12-381 DID key, using
+// the basic (NUL) scheme of the IETF BLS signature draft:
+//   - G2 keys verify 48-byte compressed G1 signatures, hashing message to G1
+//     with DefaultBLSG1DST
+//   - G1 keys verify 96-byte compressed G2 signatures, hashing message to G2
+//     with DefaultBLSG2DST
+//
+// The hash-to-curve domain separation tag can be changed with WithBLSDST, e.g.
+// to the proof-of-possession (POP) ciphersuite. Malformed or invalid signatures
+// report false with a nil error. Key agreement keys (X25519) return
+// ErrKeyAgreementKeyNotSignable.
+//
+// BLS verification is only available when built with the bls build tag.
+func (dk *DIDKey) VerifyBLS(message, signature []byte, opts ...Option) (bool, error) {
+	if err := requireSignatureKey(dk.keyType); err != nil {
+		return false, err
+	}
+
+	o := applyOptions(opts)
+	_, _, g1, g2 := bls12381.Generators()
+
+	switch dk.keyType {
+	case Bls12381G2PublicKey:
+		var publicKey bls12381.G2Affine
+		if _, err := publicKey.SetBytes(dk.keyBytes); err != nil {
+			return false, ErrInvalidPointWithContext(dk.keyType)
+		}
+
+		var sig bls12381.G1Affine
+		if len(signature) != bls12381.SizeOfG1AffineCompressed {
+			return false, nil
+		}
+		if _, err := sig.SetBytes(signature); err != nil {
+			return false, nil
+		}
+
+		hashed, err := bls12381.HashToG1(message, o.blsDST(DefaultBLSG1DST))
+		if err != nil {
+			return false, err
+		}
+
+		// e(sig, g2) == e(H(m), pk)
+		var negG2 bls12381.G2Affine
+		negG2.Neg(&g2)
+		return bls12381.PairingCheck([]bls12381.G1Affine{sig, hashed}, []bls12381.G2Affine{negG2, publicKey})
+
+	case Bls12381G1PublicKey:
+		var publicKey bls12381.G1Affine
+		if _, err := publicKey.SetBytes(dk.keyBytes); err != nil {
+			return false, ErrInvalidPointWithContext(dk.keyType)
+		}
+
+		var sig bls12381.G2Affine
+		if len(signature) != bls12381.SizeOfG2AffineCompressed {
+			return false, nil
+		}
+		if _, err := sig.SetBytes(signature); err != nil {
+			return false, nil
+		}
+
+		hashed, err := bls12381.HashToG2(message, o.blsDST(DefaultBLSG2DST))
+		if err != nil {
+			return false, err
+		}
+
+		// e(g1, sig) == e(pk, H(m))
+		var negG1 bls12381.G1Affine
+		negG1.Neg(&g1)
+		return bls12381.PairingCheck([]bls12381.G1Affine{negG1, publicKey}, []bls12381.G2Affine{sig, hashed})
+
+	default:
+		return false, ErrVerificationUnsupportedWithContext(dk.keyType)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !bls
+
+package didkey
+
+// blsVerification reports whether BLS verification is compiled in
+const blsVerification = false
+
+// VerifyBLS verifies a BLS signature of message by a BLS12-381 DID key. This
+// build does not include BLS support and returns ErrBLSUnavailable for G1 and
+// G2 keys; build with the bls tag to enable it. As with the bls build, key
+// agreement keys (X25519) return ErrKeyAgreementKeyNotSignable and other key
+// types ErrVerificationUnsupported.
+func (dk *DIDKey) VerifyBLS(message, signature []byte, opts ...Option) (bool, error) {
+	if err := requireSignatureKey(dk.keyType); err != nil {
+		return false, err
+	}
+	if !isBLSKeyType(dk.keyType) {
+		return false, ErrVerificationUnsupportedWithContext(dk.keyType)
+	}
+	return false, ErrBLSUnavailable
+}
@@ -0,0 +1,36 @@
+//go:build !bls
+
+package didkey
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyBLSUnavailable(t *testing.T) {
+	dk, err := FromBytes(Bls12381G2PublicKey, blsPlaceholderKey(96))
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+
+	if _, err := dk.Verify([]byte("message"), make([]byte, 48)); !errors.Is(err, ErrBLSUnavailable) {
+		t.Errorf("Expected ErrBLSUnavailable, got %v", err)
+	}
+}
+
+func TestVerifyBLSNonBLSKey(t *testing.T) {
+	// The same errors as the bls build, whatever the build tags
+	for _, name := range []string{"Ed25519-from-spec", "P-256-test", "Secp256k1-test"} {
+		t.Run(name, func(t *testing.T) {
+			dk, err := Parse(testVectors[name].didKey)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			_, err = dk.VerifyBLS([]byte("message"), make([]byte, 48))
+			if !errors.Is(err, ErrVerificationUnsupported) || errors.Is(err, ErrBLSUnavailable) {
+				t.Errorf("Expected ErrVerificationUnsupported, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+//go:build bls
+
+package didkey
+
+import (
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+)
+
+// Ethereum consensus-spec "sign" test vector (min-pubkey-size, proof-of-possession ciphersuite)
+const (
+	ethPrivateKeyHex = "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3"
+	ethPublicKeyHex  = "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a"
+	ethMessageHex    = "0000000000000000000000000000000000000000000000000000000000000000"
+	ethSignatureHex  = "b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55"
+	ethPOPDST        = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+func TestVerifyBLSG1Key(t *testing.T) {
+	dk, err := FromBytes(Bls12381G1PublicKey, mustDecodeHex(ethPublicKeyHex))
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+
+	message := mustDecodeHex(ethMessageHex)
+	signature := mustDecodeHex(ethSignatureHex)
+
+	valid, err := dk.Verify(message, signature, WithBLSDST(ethPOPDST))
+	if err != nil || !valid {
+		t.Errorf("Expected valid signature, got %v, %v", valid, err)
+	}
+
+	// The basic ciphersuite uses a different DST, so the same signature must not verify
+	valid, err = dk.Verify(message, signature)
+	if err != nil || valid {
+		t.Errorf("Expected invalid signature under the default DST, got %v, %v", valid, err)
+	}
+
+	tampered := append([]byte{}, message...)
+	tampered[0] = 1
+	valid, err = dk.Verify(tampered, signature, WithBLSDST(ethPOPDST))
+	if err != nil || valid {
+		t.Errorf("Expected invalid signature for tampered message, got %v, %v", valid, err)
+	}
+}
+
+func TestVerifyBLSG2Key(t *testing.T) {
+	privateKey := new(big.Int).SetBytes(mustDecodeHex(ethPrivateKeyHex))
+	message := []byte("did:key BLS test")
+
+	// pk = sk * g2, sig = sk * H(m) in G1
+	_, _, _, g2 := bls12381.Generators()
+	var publicKey bls12381.G2Affine
+	publicKey.ScalarMultiplication(&g2, privateKey)
+
+	hashed, err := bls12381.HashToG1(message, []byte(DefaultBLSG1DST))
+	if err != nil {
+		t.Fatalf("HashToG1 failed: %v", err)
+	}
+	var sig bls12381.G1Affine
+	sig.ScalarMultiplication(&hashed, privateKey)
+
+	publicKeyBytes := publicKey.Bytes()
+	dk, err := FromBytes(Bls12381G2PublicKey, publicKeyBytes[:])
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+
+	sigBytes := sig.Bytes()
+	valid, err := dk.Verify(message, sigBytes[:])
+	if err != nil || !valid {
+		t.Errorf("Expected valid signature, got %v, %v", valid, err)
+	}
+
+	valid, err = dk.Verify([]byte("other message"), sigBytes[:])
+	if err != nil || valid {
+		t.Errorf("Expected invalid signature for other message, got %v, %v", valid, err)
+	}
+
+	valid, err = dk.Verify(message, sigBytes[:10])
+	if err != nil || valid {
+		t.Errorf("Expected invalid signature for truncated signature, got %v, %v", valid, err)
+	}
+}
+
+func TestVerifyBLSInvalidKey(t *testing.T) {
+	dk, err := FromBytes(Bls12381G1PublicKey, blsPlaceholderKey(48))
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+
+	if _, err := dk.VerifyBLS([]byte("message"), make([]byte, 96)); err == nil {
+		t.Errorf("Expected error for invalid public key")
+	}
+}
@@ -0,0 +1,29 @@
+package didkey
+
+import (
+	"slices"
+)
+
+// Version is the version of the library
+const Version = "0.2.0"
+
+// CapabilityReport describes the key types and optional features compiled into the library
+type CapabilityReport struct {
+	Version         string    // Library version
+	KeyTypes        []KeyType // Key types accepted by Encode and Decode
+	PointValidation bool      // On-curve validation of EC keys
+	BLSVerification bool      // BLS12-381 signature verification (bls build tag)
+	PostQuantum     bool      // Post-quantum key types
+}
+
+// Capabilities reports the key types and optional features available in this
+// build, which can differ between deployments built with different build tags
+func Capabilities() CapabilityReport {
+	return CapabilityReport{
+		Version:         Version,
+		KeyTypes:        slices.Clone(supportedKeyTypes),
+		PointValidation: true,
+		BLSVerification: blsVerification,
+		PostQuantum:     false,
+	}
+}
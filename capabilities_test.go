@@ -0,0 +1,43 @@
+package didkey
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	report := Capabilities()
+
+	if report.Version != Version {
+		t.Errorf("Expected version %s, got %s", Version, report.Version)
+	}
+
+	coreKeyTypes := []KeyType{
+		Ed25519PublicKey,
+		X25519PublicKey,
+		Secp256k1PublicKey,
+		Bls12381G1PublicKey,
+		Bls12381G2PublicKey,
+		P256PublicKey,
+		P384PublicKey,
+	}
+	for _, keyType := range coreKeyTypes {
+		if !slices.Contains(report.KeyTypes, keyType) {
+			t.Errorf("Expected %s to be reported", keyType)
+		}
+	}
+
+	if !report.PointValidation {
+		t.Errorf("Expected point validation to be reported")
+	}
+
+	if report.BLSVerification != blsVerification {
+		t.Errorf("Expected BLS verification %v, got %v", blsVerification, report.BLSVerification)
+	}
+
+	// The report must not alias internal state
+	report.KeyTypes[0] = 0
+	if Capabilities().KeyTypes[0] != coreKeyTypes[0] {
+		t.Errorf("Mutating the report changed the supported key types")
+	}
+}
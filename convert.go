@@ -0,0 +1,71 @@
+package didkey
+
+// ConvertVerificationMethods rewrites every verification method of the document
+// to the target type: Multikey, JsonWebKey2020 or Ed25519VerificationKey2018.
+// The public key material is recomputed from the decoded key; ids, controllers
+// and verification relationships are kept, and @context is updated to match.
+//
+// X25519 key agreement keys have no Ed25519VerificationKey2018 form, so
+// converting to it uses the companion X25519KeyAgreementKey2019 type for them.
+// Keys the target type cannot represent, such as a secp256k1 key as
+// Ed25519VerificationKey2018 or a BLS12-381 key as JsonWebKey2020, fail with
+// ErrIncompatibleVerificationMethodType. The document is only modified if
+// every verification method converts.
+func (d *Document) ConvertVerificationMethods(target string) error {
+	switch target {
+	case MultikeyType, JSONWebKey2020Type, Ed25519VerificationKey2018Type:
+	default:
+		return ErrUnsupportedVerificationMethodTypeWithContext(target)
+	}
+
+	if len(d.VerificationMethod) == 0 {
+		return ErrNoVerificationMethods
+	}
+
+	converted := make([]VerificationMethod, len(d.VerificationMethod))
+	var primaryKeyType KeyType
+	for i := range d.VerificationMethod {
+		dk, err := d.VerificationMethod[i].PublicKey()
+		if err != nil {
+			return err
+		}
+
+		if converted[i], err = convertVerificationMethod(d.VerificationMethod[i], dk, target); err != nil {
+			return err
+		}
+
+		if i == 0 {
+			primaryKeyType = dk.keyType
+		}
+	}
+
+	d.Context = ContextsForKeyType(primaryKeyType, converted[0].Type)
+	d.VerificationMethod = converted
+	return nil
+}
+
+func convertVerificationMethod(vm VerificationMethod, dk *DIDKey, target string) (VerificationMethod, error) {
+	out := VerificationMethod{ID: vm.ID, Type: target, Controller: vm.Controller}
+
+	switch target {
+	case MultikeyType:
+		out.PublicKeyMultibase = dk.Fingerprint()
+	case JSONWebKey2020Type:
+		jwk, err := dk.JWK()
+		if err != nil {
+			return VerificationMethod{}, ErrIncompatibleVerificationMethodTypeWithContext(dk.keyType, target)
+		}
+		out.PublicKeyJwk = jwk
+	case Ed25519VerificationKey2018Type:
+		switch dk.keyType {
+		case Ed25519PublicKey:
+		case X25519PublicKey:
+			out.Type = X25519KeyAgreementKey2019Type
+		default:
+			return VerificationMethod{}, ErrIncompatibleVerificationMethodTypeWithContext(dk.keyType, target)
+		}
+		out.PublicKeyBase58 = encodeBase58BTC(dk.keyBytes)
+	}
+
+	return out, nil
+}
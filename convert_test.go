@@ -0,0 +1,215 @@
+package didkey
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConvertVerificationMethods(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	resolve := func(didKey string) *Document {
+		doc, err := ResolveDocument(didKey)
+		if err != nil {
+			t.Fatalf("ResolveDocument failed: %v", err)
+		}
+		return doc
+	}
+
+	t.Run("Multikey to JsonWebKey2020", func(t *testing.T) {
+		doc := resolve(did)
+		if err := doc.ConvertVerificationMethods(JSONWebKey2020Type); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !slices.Equal(doc.Context, ContextsForKeyType(Ed25519PublicKey, JSONWebKey2020Type)) {
+			t.Errorf("Unexpected context %v", doc.Context)
+		}
+
+		expected := []JWK{
+			{Kty: "OKP", Crv: "Ed25519", X: "Lm_M42cB3HkUiODQsXRcweM6TByfzEHGO9ND274JcOY"},
+			{Kty: "OKP", Crv: "X25519", X: "bl_3kgKpz9jgsg350CNuHa_kQL3B60Gi-98WmdQW2h8"},
+		}
+		for i, vm := range doc.VerificationMethod {
+			if vm.Type != JSONWebKey2020Type || vm.PublicKeyMultibase != "" {
+				t.Errorf("Unexpected verification method %+v", vm)
+			}
+			if vm.PublicKeyJwk == nil || *vm.PublicKeyJwk != expected[i] {
+				t.Errorf("Expected JWK %+v, got %+v", expected[i], vm.PublicKeyJwk)
+			}
+		}
+
+		jsonBytes, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if !strings.Contains(string(jsonBytes), `"publicKeyJwk":{"kty":"OKP","crv":"Ed25519"`) {
+			t.Errorf("Expected publicKeyJwk in %s", jsonBytes)
+		}
+	})
+
+	t.Run("Multikey to Ed25519VerificationKey2018", func(t *testing.T) {
+		doc := resolve(did)
+		if err := doc.ConvertVerificationMethods(Ed25519VerificationKey2018Type); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !slices.Equal(doc.Context, ContextsForKeyType(Ed25519PublicKey, Ed25519VerificationKey2018Type)) {
+			t.Errorf("Unexpected context %v", doc.Context)
+		}
+
+		expected := []VerificationMethod{
+			{
+				ID:              doc.VerificationMethod[0].ID,
+				Type:            Ed25519VerificationKey2018Type,
+				Controller:      did,
+				PublicKeyBase58: "48GdbJyVULjHDaBNS6ct9oAGtckZUS5v8asrPzvZ7R1w",
+			},
+			{
+				ID:              doc.VerificationMethod[1].ID,
+				Type:            X25519KeyAgreementKey2019Type,
+				Controller:      did,
+				PublicKeyBase58: "8RrinpnzRDqzUjzZuHsmNJUYbzsK1eqkQB5e5SgCvKP4",
+			},
+		}
+		if !slices.Equal(doc.VerificationMethod, expected) {
+			t.Errorf("Expected %+v, got %+v", expected, doc.VerificationMethod)
+		}
+	})
+
+	t.Run("back to Multikey", func(t *testing.T) {
+		for _, via := range []string{JSONWebKey2020Type, Ed25519VerificationKey2018Type} {
+			doc := resolve(did)
+			if err := doc.ConvertVerificationMethods(via); err != nil {
+				t.Fatalf("Convert to %s failed: %v", via, err)
+			}
+			if err := doc.ConvertVerificationMethods(MultikeyType); err != nil {
+				t.Fatalf("Convert from %s failed: %v", via, err)
+			}
+
+			if !doc.Equal(resolve(did)) {
+				t.Errorf("Expected %s round trip to restore the resolved document", via)
+			}
+		}
+	})
+
+	t.Run("JsonWebKey2020 and Ed25519VerificationKey2018", func(t *testing.T) {
+		doc := resolve(did)
+		for _, target := range []string{JSONWebKey2020Type, Ed25519VerificationKey2018Type, JSONWebKey2020Type} {
+			before := doc.VerificationMethod[0].ID
+			if err := doc.ConvertVerificationMethods(target); err != nil {
+				t.Fatalf("Convert to %s failed: %v", target, err)
+			}
+			if doc.VerificationMethod[0].ID != before {
+				t.Errorf("Expected id %s to be kept, got %s", before, doc.VerificationMethod[0].ID)
+			}
+		}
+
+		expected := resolve(did)
+		if err := expected.ConvertVerificationMethods(JSONWebKey2020Type); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !doc.Equal(expected) {
+			t.Errorf("Expected %+v, got %+v", expected, doc)
+		}
+	})
+
+	t.Run("EC keys through JsonWebKey2020", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		for _, keyType := range []KeyType{Secp256k1PublicKey, P256PublicKey, P384PublicKey} {
+			didKey, err := Encode(keyType, randomValidKey(rng, keyType))
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+
+			doc := resolve(didKey)
+			if err := doc.ConvertVerificationMethods(JSONWebKey2020Type); err != nil {
+				t.Fatalf("Convert %s failed: %v", keyType, err)
+			}
+			if doc.VerificationMethod[0].PublicKeyJwk.Kty != "EC" {
+				t.Errorf("Expected EC JWK, got %+v", doc.VerificationMethod[0].PublicKeyJwk)
+			}
+
+			if err := doc.ConvertVerificationMethods(MultikeyType); err != nil {
+				t.Fatalf("Convert %s back failed: %v", keyType, err)
+			}
+			if !doc.Equal(resolve(didKey)) {
+				t.Errorf("Expected %s round trip to restore the resolved document", keyType)
+			}
+		}
+	})
+
+	t.Run("lossy conversions", func(t *testing.T) {
+		bls, err := Encode(Bls12381G2PublicKey, blsPlaceholderKey(96))
+		if err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+
+		tests := []struct {
+			didKey string
+			target string
+		}{
+			{didKey: testVectors["Secp256k1-test"].didKey, target: Ed25519VerificationKey2018Type},
+			{didKey: testVectors["P-256-test"].didKey, target: Ed25519VerificationKey2018Type},
+			{didKey: bls, target: JSONWebKey2020Type},
+			{didKey: bls, target: Ed25519VerificationKey2018Type},
+		}
+
+		for _, tt := range tests {
+			doc := resolve(tt.didKey)
+			err := doc.ConvertVerificationMethods(tt.target)
+			if !errors.Is(err, ErrIncompatibleVerificationMethodType) {
+				t.Errorf("%s to %s: expected ErrIncompatibleVerificationMethodType, got %v", tt.didKey, tt.target, err)
+			}
+
+			if !doc.Equal(resolve(tt.didKey)) {
+				t.Errorf("%s to %s: expected the document to be unchanged", tt.didKey, tt.target)
+			}
+		}
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		doc := resolve(did)
+		if err := doc.ConvertVerificationMethods("RsaVerificationKey2018"); !errors.Is(err, ErrUnsupportedVerificationMethodType) {
+			t.Errorf("Expected ErrUnsupportedVerificationMethodType, got %v", err)
+		}
+	})
+}
+
+func TestVerificationMethodPublicKeyJWK(t *testing.T) {
+	tests := []struct {
+		name string
+		jwk  *JWK
+		err  error
+	}{
+		{name: "missing", jwk: nil, err: ErrInvalidJWK},
+		{name: "bad base64", jwk: &JWK{Kty: "OKP", Crv: "Ed25519", X: "!"}, err: ErrInvalidJWK},
+		{name: "unknown curve", jwk: &JWK{Kty: "EC", Crv: "P-521", X: "AA", Y: "AA"}, err: ErrInvalidJWK},
+		{name: "RSA", jwk: &JWK{Kty: "RSA"}, err: ErrInvalidJWK},
+		{name: "short coordinates", jwk: &JWK{Kty: "EC", Crv: "P-256", X: "AA", Y: "AA"}, err: ErrInvalidCoordinateSize},
+		{name: "wrong Ed25519 size", jwk: &JWK{Kty: "OKP", Crv: "Ed25519", X: "AAAA"}, err: ErrInvalidKeySize},
+		{
+			name: "off curve",
+			jwk: &JWK{
+				Kty: "EC",
+				Crv: "P-256",
+				X:   "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE",
+				Y:   "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAE",
+			},
+			err: ErrInvalidPoint,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm := VerificationMethod{Type: JSONWebKey2020Type, PublicKeyJwk: tt.jwk}
+			if _, err := vm.PublicKey(); !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
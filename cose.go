@@ -0,0 +1,57 @@
+package didkey
+
+// COSE_Key labels and values (RFC 9052, RFC 9053, RFC 8812)
+const (
+	coseKeyKty = 1
+	coseKeyCrv = -1
+	coseKeyX   = -2
+	coseKeyY   = -3
+
+	coseKtyOKP = 1
+	coseKtyEC2 = 2
+
+	coseCrvP256      = 1
+	coseCrvP384      = 2
+	coseCrvX25519    = 4
+	coseCrvEd25519   = 6
+	coseCrvSecp256k1 = 8
+)
+
+// COSEKey returns the public COSE_Key (RFC 9052) of the DID key as a map from
+// integer labels to values, ready for CBOR encoding. Ed25519 and X25519 keys
+// map to OKP keys; secp256k1, P-256 and P-384 keys map to EC2 keys with both
+// coordinates. Coordinates are byte slices. BLS12-381 keys have no COSE_Key
+// representation.
+func (dk *DIDKey) COSEKey() (map[int]any, error) {
+	switch dk.keyType {
+	case Ed25519PublicKey:
+		return map[int]any{coseKeyKty: coseKtyOKP, coseKeyCrv: coseCrvEd25519, coseKeyX: dk.Bytes()}, nil
+	case X25519PublicKey:
+		return map[int]any{coseKeyKty: coseKtyOKP, coseKeyCrv: coseCrvX25519, coseKeyX: dk.Bytes()}, nil
+	}
+
+	var crv int
+	switch dk.keyType {
+	case Secp256k1PublicKey:
+		crv = coseCrvSecp256k1
+	case P256PublicKey:
+		crv = coseCrvP256
+	case P384PublicKey:
+		crv = coseCrvP384
+	default:
+		return nil, ErrCOSEUnsupportedWithContext(dk.keyType)
+	}
+
+	curve, _ := curveFor(dk.keyType)
+	x, y, ok := curve.decompress(dk.keyBytes)
+	if !ok {
+		return nil, ErrInvalidPointWithContext(dk.keyType)
+	}
+
+	return map[int]any{
+		coseKeyKty: coseKtyEC2,
+		coseKeyCrv: crv,
+		coseKeyX:   x.FillBytes(make([]byte, curve.size)),
+		coseKeyY:   y.FillBytes(make([]byte, curve.size)),
+	}, nil
+}
@@ -0,0 +1,85 @@
+package didkey
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCOSEKey(t *testing.T) {
+	t.Run("Ed25519", func(t *testing.T) {
+		tv := testVectors["Ed25519-from-spec"]
+		dk, err := Parse(tv.didKey)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		key, err := dk.COSEKey()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(key) != 3 || key[1] != 1 || key[-1] != 6 {
+			t.Errorf("Expected kty OKP (1) and crv Ed25519 (6), got %v", key)
+		}
+
+		if x, _ := key[-2].([]byte); !bytes.Equal(x, mustDecodeHex(tv.keyHex)) {
+			t.Errorf("Expected x %s, got %x", tv.keyHex, key[-2])
+		}
+	})
+
+	t.Run("P-256", func(t *testing.T) {
+		dk, err := Parse(testVectors["P-256-test"].didKey)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		key, err := dk.COSEKey()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(key) != 4 || key[1] != 2 || key[-1] != 1 {
+			t.Errorf("Expected kty EC2 (2) and crv P-256 (1), got %v", key)
+		}
+
+		x, _ := key[-2].([]byte)
+		if expected := mustDecodeHex("d0ef6c6209e4e3d0de5e555b9b3f7e3c5a4c7b1e9e2d8c3f4a5b6c7d8e9f01a0"); !bytes.Equal(x, expected) {
+			t.Errorf("Expected x %x, got %x", expected, x)
+		}
+
+		y, _ := key[-3].([]byte)
+		if expected := mustDecodeHex("fec9fb6ffffc5da7366e39d12d0ebafd2eac34866e1762d60bd0d5419b7ca958"); !bytes.Equal(y, expected) {
+			t.Errorf("Expected y %x, got %x", expected, y)
+		}
+	})
+
+	t.Run("curve identifiers", func(t *testing.T) {
+		expected := map[string]int{
+			testVectors["Secp256k1-test"].didKey:                       8,
+			"did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p": 4,
+		}
+		for didKey, crv := range expected {
+			dk, err := Parse(didKey)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			key, err := dk.COSEKey()
+			if err != nil || key[-1] != crv {
+				t.Errorf("Expected crv %d for %s, got %v, %v", crv, didKey, key, err)
+			}
+		}
+	})
+
+	t.Run("BLS12-381", func(t *testing.T) {
+		dk, err := FromBytes(Bls12381G1PublicKey, blsPlaceholderKey(48))
+		if err != nil {
+			t.Fatalf("FromBytes failed: %v", err)
+		}
+
+		if _, err := dk.COSEKey(); !errors.Is(err, ErrCOSEUnsupported) {
+			t.Errorf("Expected ErrCOSEUnsupported, got %v", err)
+		}
+	})
+}
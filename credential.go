@@ -0,0 +1,236 @@
+package didkey
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"slices"
+)
+
+// Data Integrity cryptosuites supported by VerifyCredentialProof
+const (
+	// EdDSACryptosuite signs with Ed25519 keys
+	EdDSACryptosuite = "eddsa-jcs-2022"
+
+	// ECDSACryptosuite signs with P-256 (ES256) or P-384 (ES384) keys
+	ECDSACryptosuite = "ecdsa-jcs-2019"
+
+	dataIntegrityProofType = "DataIntegrityProof"
+)
+
+// Data Integrity cryptosuites using RDF Dataset Canonicalization. They are
+// recognized by ValidForCryptosuite, but VerifyCredentialProof cannot verify them.
+const (
+	// EdDSARDFCCryptosuite signs with Ed25519 keys
+	EdDSARDFCCryptosuite = "eddsa-rdfc-2022"
+
+	// ECDSARDFCCryptosuite signs with P-256 or P-384 keys
+	ECDSARDFCCryptosuite = "ecdsa-rdfc-2019"
+)
+
+// Proof is a Data Integrity proof of a Verifiable Credential
+type Proof struct {
+	Type               string `json:"type"`
+	Cryptosuite        string `json:"cryptosuite"`
+	Created            string `json:"created,omitempty"`
+	Expires            string `json:"expires,omitempty"`
+	VerificationMethod string `json:"verificationMethod"`
+	ProofPurpose       string `json:"proofPurpose"`
+	Domain             string `json:"domain,omitempty"`
+	Challenge          string `json:"challenge,omitempty"`
+	Nonce              string `json:"nonce,omitempty"`
+	ProofValue         string `json:"proofValue,omitempty"`
+}
+
+// VerifyCredentialProof reports whether proof is a valid Data Integrity proof of
+// the credential by the DID key of its issuer. The proof's verificationMethod
+// must be a did:key URL whose DID is the credential issuer, and its cryptosuite
+// must match the key type: eddsa-jcs-2022 for Ed25519, ecdsa-jcs-2019 for P-256
+// and P-384. Both cryptosuites canonicalize with JCS (RFC 8785); the RDF
+// canonicalization suites are not supported.
+//
+// A "proof" member of the credential is ignored. As with Verify, an invalid
+// signature reports false with a nil error.
+func VerifyCredentialProof(credential []byte, proof Proof, opts ...Option) (bool, error) {
+	if proof.Type != dataIntegrityProofType {
+		return false, ErrUnsupportedProofWithContext("type " + proof.Type)
+	}
+	if proof.Cryptosuite != EdDSACryptosuite && proof.Cryptosuite != ECDSACryptosuite {
+		return false, ErrUnsupportedProofWithContext("cryptosuite " + proof.Cryptosuite)
+	}
+
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(credential, &document); err != nil {
+		return false, ErrInvalidCredentialWithContext(err.Error())
+	}
+	delete(document, "proof")
+
+	issuer, err := credentialIssuer(document["issuer"])
+	if err != nil {
+		return false, err
+	}
+
+	did, _, err := SplitSigningMethodURI(proof.VerificationMethod, opts...)
+	if err != nil {
+		return false, err
+	}
+	if did != issuer {
+		return false, ErrIssuerMismatchWithContext(issuer, did)
+	}
+
+	dk, err := Parse(did, opts...)
+	if err != nil {
+		return false, err
+	}
+	if !slices.Contains(cryptosuiteKeyTypes[proof.Cryptosuite], dk.keyType) {
+		return false, ErrCryptosuiteMismatchWithContext(proof.Cryptosuite, dk.keyType)
+	}
+	// Only the signing relationships: the keyAgreement of Ed25519 keys belongs
+	// to the derived X25519 key
+	if !slices.Contains(signingRelationships, proof.ProofPurpose) {
+		return false, ErrInvalidProofWithContext("unsupported proof purpose " + proof.ProofPurpose)
+	}
+
+	signature, err := decodeProofValue(proof.ProofValue)
+	if err != nil {
+		return false, err
+	}
+
+	hashData, err := proofHashData(document, proof, dk.keyType)
+	if err != nil {
+		return false, err
+	}
+
+	// For ECDSA keys, Verify hashes hashData once more as ECDSA signing does
+	// The cryptosuite fixes the hash, whatever WithHash says
+	return dk.Verify(hashData, signature, append(slices.Clip(opts), WithHash(0))...)
+}
+
+// cryptosuiteKeyTypes lists the key types each cryptosuite signs with. They are
+// the same for the JCS and RDF canonicalization variants.
+var cryptosuiteKeyTypes = map[string][]KeyType{
+	EdDSACryptosuite:     {Ed25519PublicKey},
+	ECDSACryptosuite:     {P256PublicKey, P384PublicKey},
+	EdDSARDFCCryptosuite: {Ed25519PublicKey},
+	ECDSARDFCCryptosuite: {P256PublicKey, P384PublicKey},
+}
+
+// ValidForCryptosuite checks that the key type of a DID key can sign with a
+// Data Integrity cryptosuite, so that an issuer and suite that do not belong
+// together are rejected before any proof is created or verified:
+//   - eddsa-jcs-2022, eddsa-rdfc-2022: Ed25519
+//   - ecdsa-jcs-2019, ecdsa-rdfc-2019: P-256, P-384
+//
+// Mismatched key types return ErrCryptosuiteMismatch and unknown cryptosuites
+// ErrUnsupportedProof.
+func ValidForCryptosuite(didKey, cryptosuite string, opts ...Option) error {
+	keyTypes, ok := cryptosuiteKeyTypes[cryptosuite]
+	if !ok {
+		return ErrUnsupportedProofWithContext("cryptosuite " + cryptosuite)
+	}
+
+	keyType, _, err := Decode(didKey, opts...)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(keyTypes, keyType) {
+		return ErrCryptosuiteMismatchWithContext(cryptosuite, keyType)
+	}
+	return nil
+}
+
+// DataIntegrityProofKey returns the Multikey publicKeyMultibase of the DID key for
+// a Data Integrity verification method, which is its fingerprint. Only key types
+// of the eddsa-2022 (Ed25519) and ecdsa-2019 (P-256, P-384) cryptosuites are
+// supported; others return ErrNoDataIntegrityCryptosuite.
+func (dk *DIDKey) DataIntegrityProofKey() (string, error) {
+	for _, keyTypes := range cryptosuiteKeyTypes {
+		if slices.Contains(keyTypes, dk.keyType) {
+			return dk.Fingerprint(), nil
+		}
+	}
+
+	return "", ErrNoDataIntegrityCryptosuiteWithContext(dk.keyType)
+}
+
+// credentialIssuer returns the issuer id, given either as a string or as an
+// object with an id
+func credentialIssuer(raw json.RawMessage) (string, error) {
+	if raw == nil {
+		return "", ErrInvalidCredentialWithContext("no issuer")
+	}
+
+	var issuer string
+	if err := json.Unmarshal(raw, &issuer); err == nil {
+		return issuer, nil
+	}
+
+	var object struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &object); err != nil || object.ID == "" {
+		return "", ErrInvalidCredentialWithContext("issuer must be a string or an object with an id")
+	}
+	return object.ID, nil
+}
+
+// decodeProofValue decodes a base58-btc multibase proof value
+func decodeProofValue(proofValue string) ([]byte, error) {
+	if len(proofValue) < 2 || proofValue[0] != 'z' {
+		return nil, ErrInvalidProofWithContext("proofValue must be base58-btc multibase")
+	}
+
+	signature, err := decodeBase58BTC(proofValue[1:])
+	if err != nil {
+		return nil, ErrInvalidProofWithContext("proofValue: " + err.Error())
+	}
+	return signature, nil
+}
+
+// proofHashData returns the data signed by the JCS cryptosuites: the hash of the
+// canonical proof configuration followed by the hash of the canonical
+// credential. P-384 keys hash with SHA-384, others with SHA-256.
+func proofHashData(document map[string]json.RawMessage, proof Proof, keyType KeyType) ([]byte, error) {
+	proof.ProofValue = ""
+	proofJSON, err := json.Marshal(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	// The proof configuration takes the @context of the credential
+	if context, ok := document["@context"]; ok {
+		var config map[string]json.RawMessage
+		if err := json.Unmarshal(proofJSON, &config); err != nil {
+			return nil, err
+		}
+		config["@context"] = context
+		if proofJSON, err = json.Marshal(config); err != nil {
+			return nil, err
+		}
+	}
+
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashData bytes.Buffer
+	for _, data := range [][]byte{proofJSON, documentJSON} {
+		canonical, err := canonicalizeJSON(data)
+		if err != nil {
+			return nil, ErrInvalidCredentialWithContext(err.Error())
+		}
+
+		if keyType == P384PublicKey {
+			sum := sha512.Sum384(canonical)
+			hashData.Write(sum[:])
+		} else {
+			sum := sha256.Sum256(canonical)
+			hashData.Write(sum[:])
+		}
+	}
+
+	return hashData.Bytes(), nil
+}
@@ -0,0 +1,255 @@
+package didkey
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testCredential builds a credential issued by did, formatted differently from
+// its canonical form to exercise JCS canonicalization
+func testCredential(did string) string {
+	return `{
+		"credentialSubject": {"score": 1.50, "age": 30, "name": "Alice <&> é", "id": "did:example:alice"},
+		"validFrom": "2026-01-01T00:00:00Z",
+		"issuer": "` + did + `",
+		"type": ["VerifiableCredential"],
+		"@context": ["https://www.w3.org/ns/credentials/v2"]
+	}`
+}
+
+func testProof(did, cryptosuite, proofValue string) Proof {
+	return Proof{
+		Type:               "DataIntegrityProof",
+		Cryptosuite:        cryptosuite,
+		Created:            "2026-01-01T00:00:00Z",
+		VerificationMethod: did + "#" + did[len(DIDKeyPrefix):],
+		ProofPurpose:       AssertionMethod,
+		ProofValue:         proofValue,
+	}
+}
+
+func TestVerifyCredentialProof(t *testing.T) {
+	// Signed with an independent implementation of the cryptosuites
+	tests := []struct {
+		name        string
+		did         string
+		cryptosuite string
+		proofValue  string
+	}{
+		{
+			name:        "eddsa-jcs-2022",
+			did:         "did:key:z6MkqG3Rh2KK8L2A4zW7cB6pvPsKHa8L7mn6UHpKyjemyUNj",
+			cryptosuite: EdDSACryptosuite,
+			proofValue:  "z2vzjJfgsAxJdAPaTVaiXve99bmisuegVL7DBiDeEnw7hyzzdxFYjnkCaTe9xDhRm8n6oMC6ReSkKAef5zGrAGLaN",
+		},
+		{
+			name:        "ecdsa-jcs-2019",
+			did:         "did:key:zDnaetE2G1Hn2f6dzLNPM6drkBCCaE8GJqpSJ1a9yNjkcRBDf",
+			cryptosuite: ECDSACryptosuite,
+			proofValue:  "z4tRHCfZVTFPKkVWu3i3TNEAbzpQPbNyCEWGz4WdzvNgakK8VoXupikoFnmDnEZcrcHDy7PKRpk6NuQgZ3SLFDCot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			credential := testCredential(tt.did)
+			proof := testProof(tt.did, tt.cryptosuite, tt.proofValue)
+
+			if valid, err := VerifyCredentialProof([]byte(credential), proof); err != nil || !valid {
+				t.Fatalf("Expected valid proof, got %v, %v", valid, err)
+			}
+
+			// An embedded proof is not part of the signed data
+			secured := strings.Replace(credential, `"validFrom"`, `"proof": {"type": "DataIntegrityProof"}, "validFrom"`, 1)
+			if valid, err := VerifyCredentialProof([]byte(secured), proof); err != nil || !valid {
+				t.Errorf("Expected valid proof with embedded proof member, got %v, %v", valid, err)
+			}
+
+			tampered := strings.Replace(credential, "Alice", "Mallory", 1)
+			if valid, err := VerifyCredentialProof([]byte(tampered), proof); err != nil || valid {
+				t.Errorf("Expected invalid proof for tampered credential, got %v, %v", valid, err)
+			}
+
+			tamperedProof := proof
+			tamperedProof.Created = "2026-01-02T00:00:00Z"
+			if valid, err := VerifyCredentialProof([]byte(credential), tamperedProof); err != nil || valid {
+				t.Errorf("Expected invalid proof for tampered proof options, got %v, %v", valid, err)
+			}
+		})
+	}
+}
+
+func TestVerifyCredentialProofErrors(t *testing.T) {
+	edDID := "did:key:z6MkqG3Rh2KK8L2A4zW7cB6pvPsKHa8L7mn6UHpKyjemyUNj"
+	p256DID := "did:key:zDnaetE2G1Hn2f6dzLNPM6drkBCCaE8GJqpSJ1a9yNjkcRBDf"
+	proofValue := "z2vzjJfgsAxJdAPaTVaiXve99bmisuegVL7DBiDeEnw7hyzzdxFYjnkCaTe9xDhRm8n6oMC6ReSkKAef5zGrAGLaN"
+	valid := testProof(edDID, EdDSACryptosuite, proofValue)
+
+	with := func(modify func(*Proof)) Proof {
+		proof := valid
+		modify(&proof)
+		return proof
+	}
+
+	t.Run("issuer object", func(t *testing.T) {
+		credential := strings.Replace(testCredential(edDID), `"`+edDID+`"`, `{"id": "`+edDID+`", "name": "Issuer"}`, 1)
+		proof := testProof(edDID, EdDSACryptosuite, "zoSMvrPgz6AGcUk3ztFz39eybwfgKzQHHzR3767tm7re4V8HiGFDfvNbF2GrbL7YAGdN6WWgaiZjhtk36V7WwkGU")
+		if ok, err := VerifyCredentialProof([]byte(credential), proof); err != nil || !ok {
+			t.Errorf("Expected valid proof, got %v, %v", ok, err)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		credential  string
+		proof       Proof
+		expectedErr error
+	}{
+		{name: "unsupported type", credential: testCredential(edDID), proof: with(func(p *Proof) { p.Type = "Ed25519Signature2020" }), expectedErr: ErrUnsupportedProof},
+		{name: "RDF cryptosuite", credential: testCredential(edDID), proof: with(func(p *Proof) { p.Cryptosuite = "eddsa-rdfc-2022" }), expectedErr: ErrUnsupportedProof},
+		{name: "issuer mismatch", credential: testCredential(p256DID), proof: valid, expectedErr: ErrIssuerMismatch},
+		{name: "cryptosuite mismatch", credential: testCredential(p256DID), proof: testProof(p256DID, EdDSACryptosuite, proofValue), expectedErr: ErrCryptosuiteMismatch},
+		{name: "not a did:key", credential: testCredential(edDID), proof: with(func(p *Proof) { p.VerificationMethod = "did:web:example.com#key-1" }), expectedErr: ErrInvalidDIDKeyPrefix},
+		{name: "fragment mismatch", credential: testCredential(edDID), proof: with(func(p *Proof) { p.VerificationMethod = edDID + "#key-1" }), expectedErr: ErrFragmentMismatch},
+		{name: "key agreement purpose", credential: testCredential(edDID), proof: with(func(p *Proof) { p.ProofPurpose = "encryption" }), expectedErr: ErrInvalidProof},
+		{name: "proofValue not multibase", credential: testCredential(edDID), proof: with(func(p *Proof) { p.ProofValue = proofValue[1:] }), expectedErr: ErrInvalidProof},
+		{name: "proofValue not base58", credential: testCredential(edDID), proof: with(func(p *Proof) { p.ProofValue = "z0OIl" }), expectedErr: ErrInvalidProof},
+		{name: "credential not JSON", credential: `{`, proof: valid, expectedErr: ErrInvalidCredential},
+		{name: "no issuer", credential: `{"type": ["VerifiableCredential"]}`, proof: valid, expectedErr: ErrInvalidCredential},
+		{name: "issuer not a string or object", credential: `{"issuer": 1}`, proof: valid, expectedErr: ErrInvalidCredential},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyCredentialProof([]byte(tt.credential), tt.proof); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyCredentialProofKeyAgreementPurpose(t *testing.T) {
+	// The keyAgreement of an Ed25519 DID key is served by the derived X25519
+	// key, so a correctly signed proof with that purpose is still rejected
+	privateKey := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	did, err := Encode(Ed25519PublicKey, privateKey.Public().(ed25519.PublicKey))
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	credential := testCredential(did)
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(credential), &document); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	proof := testProof(did, EdDSACryptosuite, "")
+	proof.ProofPurpose = KeyAgreement
+	hashData, err := proofHashData(document, proof, Ed25519PublicKey)
+	if err != nil {
+		t.Fatalf("proofHashData failed: %v", err)
+	}
+	proof.ProofValue = "z" + encodeBase58BTC(ed25519.Sign(privateKey, hashData))
+
+	if _, err := VerifyCredentialProof([]byte(credential), proof); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("Expected ErrInvalidProof, got %v", err)
+	}
+
+	// The same signature process with a signing purpose verifies
+	proof.ProofPurpose = Authentication
+	if hashData, err = proofHashData(document, proof, Ed25519PublicKey); err != nil {
+		t.Fatalf("proofHashData failed: %v", err)
+	}
+	proof.ProofValue = "z" + encodeBase58BTC(ed25519.Sign(privateKey, hashData))
+	if valid, err := VerifyCredentialProof([]byte(credential), proof); err != nil || !valid {
+		t.Errorf("Expected valid proof, got %v, %v", valid, err)
+	}
+}
+
+func TestDataIntegrityProofKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		didKey      string
+		prefix      string
+		expectedErr error
+	}{
+		{name: "Ed25519", didKey: "did:key:z6MkqG3Rh2KK8L2A4zW7cB6pvPsKHa8L7mn6UHpKyjemyUNj", prefix: "z6Mk"},
+		{name: "P-256", didKey: "did:key:zDnaetE2G1Hn2f6dzLNPM6drkBCCaE8GJqpSJ1a9yNjkcRBDf", prefix: "zDna"},
+		{name: "P-384", didKey: "did:key:z82Lm1MpAkeJcix9K8TMiLd5NMAhnwkjjCBeWHXyu3U4oT2MVJJKXkcVBgjGhnLBn2Kaau9", prefix: "z82L"},
+		{name: "secp256k1", didKey: "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme", expectedErr: ErrNoDataIntegrityCryptosuite},
+		{name: "X25519", didKey: "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p", expectedErr: ErrNoDataIntegrityCryptosuite},
+		{name: "BLS12-381 G2", didKey: "did:key:zUC7EK3ZakmukHhuncwkbySmomv3FmrkmS36E4Ks5rsb6VQSRpoCrx6Hb8e2Nk6UvJFSdyw9NK1scFXJp21gNNYFjVWNgaqyGnkyhtagagCpQb5B7tagJu3HDbjQ8h5ypoHjwBb", expectedErr: ErrNoDataIntegrityCryptosuite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dk, err := Parse(tt.didKey)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			key, err := dk.DataIntegrityProofKey()
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DataIntegrityProofKey failed: %v", err)
+			}
+			if !strings.HasPrefix(key, tt.prefix) || key != dk.Fingerprint() {
+				t.Errorf("Expected the %s... fingerprint, got %s", tt.prefix, key)
+			}
+
+			// The key is usable as publicKeyMultibase of a Multikey method
+			vm := VerificationMethod{Type: MultikeyType, PublicKeyMultibase: key}
+			if publicKey, err := vm.PublicKey(); err != nil || publicKey.String() != tt.didKey {
+				t.Errorf("Expected %s, got %v, %v", tt.didKey, publicKey, err)
+			}
+		})
+	}
+}
+
+func TestValidForCryptosuite(t *testing.T) {
+	ed25519 := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	p256 := "did:key:zDnaerDaTF5BXEavCrfRZEk316dpbLsfPDZ3WJ5hRTPFU2169"
+	p384 := "did:key:z82Lm1MpAkeJcix9K8TMiLd5NMAhnwkjjCBeWHXyu3U4oT2MVJJKXkcVBgjGhnLBn2Kaau9"
+	secp256k1 := "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme"
+
+	tests := []struct {
+		name        string
+		didKey      string
+		cryptosuite string
+		expectedErr error
+	}{
+		{name: "Ed25519 eddsa-rdfc-2022", didKey: ed25519, cryptosuite: EdDSARDFCCryptosuite},
+		{name: "Ed25519 eddsa-jcs-2022", didKey: ed25519, cryptosuite: EdDSACryptosuite},
+		{name: "P-256 ecdsa-rdfc-2019", didKey: p256, cryptosuite: ECDSARDFCCryptosuite},
+		{name: "P-384 ecdsa-jcs-2019", didKey: p384, cryptosuite: ECDSACryptosuite},
+		{name: "P-256 eddsa-rdfc-2022", didKey: p256, cryptosuite: EdDSARDFCCryptosuite, expectedErr: ErrCryptosuiteMismatch},
+		{name: "Ed25519 ecdsa-rdfc-2019", didKey: ed25519, cryptosuite: ECDSARDFCCryptosuite, expectedErr: ErrCryptosuiteMismatch},
+		{name: "secp256k1 ecdsa-rdfc-2019", didKey: secp256k1, cryptosuite: ECDSARDFCCryptosuite, expectedErr: ErrCryptosuiteMismatch},
+		{name: "unknown cryptosuite", didKey: ed25519, cryptosuite: "bbs-2023", expectedErr: ErrUnsupportedProof},
+		{name: "invalid DID key", didKey: "did:web:example.com", cryptosuite: EdDSARDFCCryptosuite, expectedErr: ErrInvalidDIDKeyPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidForCryptosuite(tt.didKey, tt.cryptosuite)
+			if tt.expectedErr == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package didkey
+
+import (
+	"crypto/ecdh"
+	"crypto/elliptic"
+	"math/big"
+	"sync"
+)
+
+// CurveOps are the elliptic curve point operations used to validate and
+// convert the keys of an EC key type. Implementations must be safe for
+// concurrent use.
+type CurveOps interface {
+	// Decompress returns the point encoded by SEC 1 compressed key bytes, or an
+	// error if they do not encode a point on the curve
+	Decompress(compressed []byte) (x, y *big.Int, err error)
+
+	// OnCurve reports whether (x, y) is a point on the curve
+	OnCurve(x, y *big.Int) bool
+
+	// Compress returns the SEC 1 compressed form of a point on the curve
+	Compress(x, y *big.Int) []byte
+}
+
+var (
+	curveOpsMu sync.RWMutex
+	curveOps   = defaultCurveOps()
+)
+
+// defaultCurveOps returns the built-in CurveOps: the standard library for the
+// NIST curves, which it supports, and a math/big implementation for secp256k1,
+// which it does not. It has an entry for every ecCurve.
+func defaultCurveOps() map[KeyType]CurveOps {
+	return map[KeyType]CurveOps{
+		Secp256k1PublicKey: secp256k1Curve,
+		P256PublicKey:      nistCurveOps{elliptic.P256(), ecdh.P256()},
+		P384PublicKey:      nistCurveOps{elliptic.P384(), ecdh.P384()},
+	}
+}
+
+// RegisterCurveOps sets the CurveOps used for the keys of an EC key type by
+// every encode, decode and conversion, e.g. to use another secp256k1 backend.
+// secp256k1 signature verification still uses the built-in point arithmetic
+// after decompressing the key with the registered ops.
+//
+// A nil ops restores the built-in implementation. It returns
+// ErrUnsupportedKeyType for key types without a curve. It is safe for
+// concurrent use with encodes and decodes on other goroutines, which use either
+// the old or the new ops, but is meant to be called once at program start,
+// ideally from an init function, so that every key is handled by the same ops.
+func RegisterCurveOps(keyType KeyType, ops CurveOps) error {
+	if _, ok := curveFor(keyType); !ok {
+		return ErrUnsupportedKeyTypeWithContext(keyType)
+	}
+	if ops == nil {
+		ops = defaultCurveOps()[keyType]
+	}
+
+	curveOpsMu.Lock()
+	defer curveOpsMu.Unlock()
+	curveOps[keyType] = ops
+	return nil
+}
+
+// CurveOpsFor returns the CurveOps registered for an EC key type, for
+// implementations that wrap it. It reports false for key types without a curve.
+func CurveOpsFor(keyType KeyType) (CurveOps, bool) {
+	curveOpsMu.RLock()
+	defer curveOpsMu.RUnlock()
+	ops, ok := curveOps[keyType]
+	return ops, ok
+}
+
+// ops returns the CurveOps registered for the key type of the curve. Every
+// curve has an entry, as defaultCurveOps has one for each and RegisterCurveOps
+// never removes them.
+func (c *ecCurve) ops() CurveOps {
+	ops, _ := CurveOpsFor(c.keyType)
+	return ops
+}
+
+// nistCurveOps implements CurveOps with the point encodings of crypto/elliptic
+// and the point validation of crypto/ecdh
+type nistCurveOps struct {
+	curve     elliptic.Curve
+	ecdhCurve ecdh.Curve
+}
+
+func (n nistCurveOps) Decompress(compressed []byte) (x, y *big.Int, err error) {
+	x, y = elliptic.UnmarshalCompressed(n.curve, compressed)
+	if x == nil {
+		return nil, nil, errNotOnCurve
+	}
+	return x, y, nil
+}
+
+func (n nistCurveOps) OnCurve(x, y *big.Int) bool {
+	size := (n.curve.Params().BitSize + 7) / 8
+	if x.Sign() < 0 || y.Sign() < 0 || x.BitLen() > 8*size || y.BitLen() > 8*size {
+		return false
+	}
+
+	// ecdh rejects coordinates that are out of range or not on the curve
+	uncompressed := make([]byte, 1+2*size)
+	uncompressed[0] = 0x04
+	x.FillBytes(uncompressed[1 : 1+size])
+	y.FillBytes(uncompressed[1+size:])
+	_, err := n.ecdhCurve.NewPublicKey(uncompressed)
+	return err == nil
+}
+
+func (n nistCurveOps) Compress(x, y *big.Int) []byte {
+	return elliptic.MarshalCompressed(n.curve, x, y)
+}
@@ -0,0 +1,178 @@
+package didkey
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNISTCurveOps(t *testing.T) {
+	tests := []struct {
+		keyType KeyType
+		keyHex  string
+	}{
+		{keyType: P256PublicKey, keyHex: testVectors["P-256-test"].keyHex},
+		{keyType: P384PublicKey, keyHex: p384GeneratorHex},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyType.String(), func(t *testing.T) {
+			ops, ok := CurveOpsFor(tt.keyType)
+			if !ok {
+				t.Fatalf("No CurveOps for %s", tt.keyType)
+			}
+			if _, ok := ops.(nistCurveOps); !ok {
+				t.Fatalf("Expected standard library ops by default, got %T", ops)
+			}
+
+			// The standard library ops agree with the math/big curve implementation
+			curve, _ := curveFor(tt.keyType)
+			keyBytes := mustDecodeHex(tt.keyHex)
+
+			x, y, err := ops.Decompress(keyBytes)
+			if err != nil {
+				t.Fatalf("Decompress failed: %v", err)
+			}
+			wantX, wantY, err := curve.Decompress(keyBytes)
+			if err != nil {
+				t.Fatalf("math/big Decompress failed: %v", err)
+			}
+			if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+				t.Errorf("Expected (%x, %x), got (%x, %x)", wantX, wantY, x, y)
+			}
+
+			if !ops.OnCurve(x, y) || ops.OnCurve(x, new(big.Int).Add(y, big.NewInt(1))) {
+				t.Errorf("OnCurve disagrees with the decompressed point")
+			}
+
+			// Coordinates congruent to the point but outside [0, p) are rejected
+			for name, coords := range map[string][2]*big.Int{
+				"x + p":    {new(big.Int).Add(x, curve.p), y},
+				"y + p":    {x, new(big.Int).Add(y, curve.p)},
+				"negative": {new(big.Int).Neg(x), y},
+				"oversize": {new(big.Int).Lsh(x, uint(8*curve.size)), y},
+			} {
+				if ops.OnCurve(coords[0], coords[1]) {
+					t.Errorf("Expected OnCurve to reject %s", name)
+				}
+			}
+			if compressed := ops.Compress(x, y); !bytes.Equal(compressed, keyBytes) {
+				t.Errorf("Expected %x, got %x", keyBytes, compressed)
+			}
+
+			// x = p is out of range
+			outOfRange := append([]byte{0x02}, curve.p.FillBytes(make([]byte, curve.size))...)
+			if _, _, err := ops.Decompress(outOfRange); err == nil {
+				t.Errorf("Expected Decompress to reject x = p")
+			}
+		})
+	}
+}
+
+// stubCurveOps is a secp256k1 provider wrapping another, counting its calls
+// and optionally rejecting every point
+type stubCurveOps struct {
+	base   CurveOps
+	calls  atomic.Int32
+	reject atomic.Bool
+}
+
+func (s *stubCurveOps) Decompress(compressed []byte) (x, y *big.Int, err error) {
+	s.calls.Add(1)
+	if s.reject.Load() {
+		return nil, nil, errors.New("stub rejects every point")
+	}
+	return s.base.Decompress(compressed)
+}
+
+func (s *stubCurveOps) OnCurve(x, y *big.Int) bool {
+	s.calls.Add(1)
+	return !s.reject.Load() && s.base.OnCurve(x, y)
+}
+
+func (s *stubCurveOps) Compress(x, y *big.Int) []byte {
+	s.calls.Add(1)
+	return s.base.Compress(x, y)
+}
+
+func TestRegisterCurveOps(t *testing.T) {
+	base, _ := CurveOpsFor(Secp256k1PublicKey)
+	stub := &stubCurveOps{base: base}
+	if err := RegisterCurveOps(Secp256k1PublicKey, stub); err != nil {
+		t.Fatalf("RegisterCurveOps failed: %v", err)
+	}
+	t.Cleanup(func() { _ = RegisterCurveOps(Secp256k1PublicKey, nil) })
+
+	didKey := testVectors["Secp256k1-test"].didKey
+	if _, _, err := Decode(didKey); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if stub.calls.Load() == 0 {
+		t.Errorf("Expected Decode to use the registered ops")
+	}
+
+	stub.reject.Store(true)
+	if _, _, err := Decode(didKey); !errors.Is(err, ErrInvalidPoint) {
+		t.Errorf("Expected ErrInvalidPoint from the rejecting ops, got %v", err)
+	}
+	uncompressed := mustDecodeHex("0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+	if _, err := Encode(Secp256k1PublicKey, uncompressed); !errors.Is(err, ErrInvalidPoint) {
+		t.Errorf("Expected Encode to use the registered ops, got %v", err)
+	}
+
+	// Other curves keep their own ops
+	if _, _, err := Decode(testVectors["P-256-test"].didKey); err != nil {
+		t.Errorf("Expected P-256 to be unaffected, got %v", err)
+	}
+
+	if err := RegisterCurveOps(Secp256k1PublicKey, nil); err != nil {
+		t.Fatalf("RegisterCurveOps failed: %v", err)
+	}
+	if _, _, err := Decode(didKey); err != nil {
+		t.Errorf("Expected the built-in ops to be restored, got %v", err)
+	}
+
+	if err := RegisterCurveOps(Ed25519PublicKey, stub); !errors.Is(err, ErrUnsupportedKeyType) {
+		t.Errorf("Expected ErrUnsupportedKeyType, got %v", err)
+	}
+}
+
+// TestRegisterCurveOpsConcurrent registers ops while other goroutines decode;
+// run with -race to check the registry locking
+func TestRegisterCurveOpsConcurrent(t *testing.T) {
+	base, _ := CurveOpsFor(Secp256k1PublicKey)
+	stub := &stubCurveOps{base: base}
+	t.Cleanup(func() { _ = RegisterCurveOps(Secp256k1PublicKey, nil) })
+
+	didKey := testVectors["Secp256k1-test"].didKey
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ops := CurveOps(stub)
+				if j%2 == 0 {
+					ops = nil
+				}
+				if err := RegisterCurveOps(Secp256k1PublicKey, ops); err != nil {
+					t.Errorf("RegisterCurveOps failed: %v", err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, _, err := Decode(didKey); err != nil {
+					t.Errorf("Decode failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,79 @@
+package didkey
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// DereferenceService dereferences a DID URL selecting a service, such as
+// did:key:z6Mk...?service=files&relativeRef=/docs, to the service endpoint URL
+// with the relativeRef parameter (if any) resolved against it.
+//
+// DID keys have no services, so with a nil doc dereferencing always fails with
+// serviceNotFound. Callers that publish the document with services pass it as
+// doc; it must have the DID of the DID URL as its id. The
+// service parameter matches a service whose id has that fragment, written
+// either absolute (did:key:z6Mk...#files) or relative (#files). With a
+// relativeRef, the service endpoint must be an absolute URL. Errors are
+// returned as *ResolutionError.
+func DereferenceService(didURL string, doc *Document, opts ...Option) (string, error) {
+	u, _, _, err := parseDIDURL(didURL, opts...)
+	if err != nil {
+		code := ResolutionInvalidDID
+		if errors.Is(err, ErrInvalidDIDURL) {
+			code = ResolutionInvalidDIDURL
+		}
+		return "", &ResolutionError{Code: code, Err: err}
+	}
+
+	query := u.QueryParams()
+	if !query.Has("service") {
+		return "", &ResolutionError{Code: ResolutionInvalidDIDURL, Err: ErrMissingServiceParam}
+	}
+	serviceID := query.Get("service")
+
+	if doc == nil {
+		return "", &ResolutionError{Code: ResolutionServiceNotFound, Err: ErrServiceNotFoundWithContext(serviceID)}
+	}
+
+	if doc.ID != u.DID {
+		return "", &ResolutionError{Code: ResolutionDocumentMismatch, Err: ErrDocumentMismatchWithContext(u.DID, doc.ID)}
+	}
+
+	service, ok := doc.findService(serviceID)
+	if !ok {
+		return "", &ResolutionError{Code: ResolutionServiceNotFound, Err: ErrServiceNotFoundWithContext(serviceID)}
+	}
+
+	if !query.Has("relativeRef") {
+		return service.ServiceEndpoint, nil
+	}
+
+	// A relative endpoint would resolve to another relative reference
+	endpoint, err := url.Parse(service.ServiceEndpoint)
+	if err != nil || !endpoint.IsAbs() {
+		return "", &ResolutionError{Code: ResolutionInvalidServiceEndpoint, Err: ErrInvalidServiceEndpointWithContext(service.ServiceEndpoint, err)}
+	}
+
+	ref, err := url.Parse(query.Get("relativeRef"))
+	if err != nil {
+		return "", &ResolutionError{Code: ResolutionInvalidDIDURL, Err: ErrInvalidDIDURLWithContext(err)}
+	}
+
+	return endpoint.ResolveReference(ref).String(), nil
+}
+
+// findService returns the service whose id has the given fragment
+func (d *Document) findService(fragment string) (Service, bool) {
+	for _, service := range d.Service {
+		id, ok := strings.CutPrefix(service.ID, d.ID)
+		if !ok && !strings.HasPrefix(service.ID, "#") {
+			continue
+		}
+		if id == "#"+fragment {
+			return service, true
+		}
+	}
+	return Service{}, false
+}
@@ -0,0 +1,132 @@
+package didkey
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDereferenceService(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	doc, err := ResolveDocument(did)
+	if err != nil {
+		t.Fatalf("ResolveDocument failed: %v", err)
+	}
+	doc.Service = []Service{
+		{ID: did + "#files", Type: "LinkedDomains", ServiceEndpoint: "https://example.com/files/"},
+		{ID: "#messages", Type: "DIDCommMessaging", ServiceEndpoint: "https://example.com/didcomm"},
+		{ID: "#relative", Type: "LinkedDomains", ServiceEndpoint: "/files/"},
+		{ID: "#malformed", Type: "LinkedDomains", ServiceEndpoint: "https://example.com/%zz"},
+	}
+
+	tests := []struct {
+		name     string
+		didURL   string
+		doc      *Document
+		expected string
+		code     string
+		err      error
+	}{
+		{
+			name:     "absolute service id",
+			didURL:   did + "?service=files",
+			doc:      doc,
+			expected: "https://example.com/files/",
+		},
+		{
+			name:     "relative service id",
+			didURL:   did + "?service=messages",
+			doc:      doc,
+			expected: "https://example.com/didcomm",
+		},
+		{
+			name:     "relativeRef",
+			didURL:   did + "?service=files&relativeRef=%2Fbar%2Fbaz.txt",
+			doc:      doc,
+			expected: "https://example.com/bar/baz.txt",
+		},
+		{
+			name:     "relativeRef without leading slash",
+			didURL:   did + "?service=files&relativeRef=bar",
+			doc:      doc,
+			expected: "https://example.com/files/bar",
+		},
+		{
+			name:   "no services",
+			didURL: did + "?service=files&relativeRef=%2Fbar",
+			code:   ResolutionServiceNotFound,
+			err:    ErrServiceNotFound,
+		},
+		{
+			name:   "unknown service",
+			didURL: did + "?service=other",
+			doc:    doc,
+			code:   ResolutionServiceNotFound,
+			err:    ErrServiceNotFound,
+		},
+		{
+			name:   "missing service parameter",
+			didURL: did + "?relativeRef=%2Fbar",
+			doc:    doc,
+			code:   ResolutionInvalidDIDURL,
+			err:    ErrMissingServiceParam,
+		},
+		{
+			name:   "document of another DID",
+			didURL: "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p?service=files",
+			doc:    doc,
+			code:   ResolutionDocumentMismatch,
+			err:    ErrDocumentMismatch,
+		},
+		{
+			name:     "relative endpoint without relativeRef",
+			didURL:   did + "?service=relative",
+			doc:      doc,
+			expected: "/files/",
+		},
+		{
+			name:   "relativeRef with relative endpoint",
+			didURL: did + "?service=relative&relativeRef=bar",
+			doc:    doc,
+			code:   ResolutionInvalidServiceEndpoint,
+			err:    ErrInvalidServiceEndpoint,
+		},
+		{
+			name:   "relativeRef with malformed endpoint",
+			didURL: did + "?service=malformed&relativeRef=bar",
+			doc:    doc,
+			code:   ResolutionInvalidServiceEndpoint,
+			err:    ErrInvalidServiceEndpoint,
+		},
+		{
+			name:   "invalid DID",
+			didURL: "did:key:zinvalid?service=files",
+			code:   ResolutionInvalidDID,
+			err:    ErrInvalidFingerprintLength,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, err := DereferenceService(tt.didURL, tt.doc)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if endpoint != tt.expected {
+					t.Errorf("Expected %s, got %s", tt.expected, endpoint)
+				}
+				return
+			}
+
+			var resolutionErr *ResolutionError
+			if !errors.As(err, &resolutionErr) || resolutionErr.Code != tt.code {
+				t.Fatalf("Expected %s ResolutionError, got %v", tt.code, err)
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 package didkey
 
 import (
+	"bytes"
+	"errors"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/multiformats/go-multibase"
 	"github.com/multiformats/go-varint"
@@ -9,53 +12,288 @@ import (
 
 const (
 	DIDKeyPrefix = "did:key:"
+
+	// MaxFingerprintChars is the length of the longest multibase fingerprint of
+	// any supported key type, that of a BLS12-381 G2 key. Decode rejects longer
+	// fingerprints with ErrFingerprintTooLong before decoding them.
+	MaxFingerprintChars = 135
+
+	// urnPrefix is the URN wrapper stripped by DecodeURN
+	urnPrefix = "urn:"
 )
 
 // Encode converts raw key bytes and key type to a DID key string
 // Format: did:key:MULTIBASE(base58-btc, MULTICODEC(public-key-type, raw-public-key-bytes))
-func Encode(keyType KeyType, keyBytes []byte) (string, error) {
+//
+// secp256k1 keys may also be given in 65-byte uncompressed form; they are
+// compressed before encoding. Errors are only returned for invalid keys: the
+// base58-btc encoding itself cannot fail.
+func Encode(keyType KeyType, keyBytes []byte, opts ...Option) (string, error) {
 	if len(keyBytes) == 0 {
 		return "", ErrEmptyKeyBytes
 	}
 
-	if err := validateKeySize(keyType, keyBytes); err != nil {
+	o := applyOptions(opts)
+	keyBytes, err := normalizeKeyBytes(keyType, keyBytes, o)
+	if err != nil {
 		return "", err
 	}
 
-	codecBytes := varint.ToUvarint(uint64(keyType))
-	multicodecBytes := make([]byte, len(codecBytes)+len(keyBytes))
-	copy(multicodecBytes, codecBytes)
-	copy(multicodecBytes[len(codecBytes):], keyBytes)
+	if err := validateKey(keyType, keyBytes, o); err != nil {
+		return "", err
+	}
+
+	return encodeDIDKey(keyType, keyBytes), nil
+}
+
+// EncodeWithCodec encodes key bytes under an arbitrary multicodec code, for key
+// types registered after this package was released. Codes of supported key
+// types are validated as by Encode; for unknown codes no size or other
+// validation occurs beyond requiring non-empty key bytes. Decode rejects DID keys
+// with unknown codes, so the result is only useful to other implementations.
+func EncodeWithCodec(codec uint64, keyBytes []byte) (string, error) {
+	if len(keyBytes) == 0 {
+		return "", ErrEmptyKeyBytes
+	}
+
+	keyType := KeyType(codec)
+	if _, ok := keySize(keyType); ok {
+		return Encode(keyType, keyBytes)
+	}
+
+	return encodeDIDKey(keyType, keyBytes), nil
+}
+
+// encodeDIDKey encodes already validated key bytes to a DID key string
+func encodeDIDKey(keyType KeyType, keyBytes []byte) string {
+	return DIDKeyPrefix + string(multibase.Base58BTC) + encodeBase58BTC(encodeMulticodec(keyType, keyBytes))
+}
+
+// Decode converts a DID key string back to key type and raw bytes. Input that
+// is not valid UTF-8, contains control characters, or whose fingerprint is too
+// short or too long for any supported key type, is rejected before decoding.
+// Failures are reported to the DecodeObserver set with WithDecodeObserver.
+func Decode(didKey string, opts ...Option) (KeyType, []byte, error) {
+	o := applyOptions(opts)
+	keyType, keyBytes, err := decode(didKey, o)
+	o.observeDecodeError(err)
+	return keyType, keyBytes, err
+}
 
-	multibaseString, err := multibase.Encode(multibase.Base58BTC, multicodecBytes)
+func decode(didKey string, o options) (KeyType, []byte, error) {
+	multicodecBytes, err := decodeDIDKey(didKey)
 	if err != nil {
-		return "", ErrMultibaseEncodeFailedWithContext(err)
+		return 0, nil, err
+	}
+
+	return decodeMulticodec(multicodecBytes, o)
+}
+
+// DecodeExpecting decodes a DID key like Decode, returning only its key bytes.
+// A DID key of any other key type than expected fails with ErrKeyTypeMismatch,
+// naming both types.
+func DecodeExpecting(didKey string, expected KeyType, opts ...Option) ([]byte, error) {
+	keyType, keyBytes, err := Decode(didKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyType != expected {
+		return nil, ErrKeyTypeMismatchWithContext(expected, keyType)
+	}
+
+	return keyBytes, nil
+}
+
+// DecodeDetails is the result of DecodeResult. Fields may be added in later
+// versions without breaking callers.
+type DecodeDetails struct {
+	KeyType        KeyType
+	KeyBytes       []byte
+	Fingerprint    string // Multibase method-specific identifier, e.g. z6Mk...
+	MulticodecCode uint64
+	Name           string // Multicodec name, e.g. ed25519-pub
+}
+
+// DecodeResult decodes a DID key like Decode, returning the result as a struct
+// that can grow new fields. The type is named DecodeDetails, as Go does not
+// allow a type and a function with the same name.
+func DecodeResult(didKey string, opts ...Option) (*DecodeDetails, error) {
+	keyType, keyBytes, err := Decode(didKey, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	return DIDKeyPrefix + multibaseString, nil
+	return &DecodeDetails{
+		KeyType:        keyType,
+		KeyBytes:       keyBytes,
+		Fingerprint:    didKey[len(DIDKeyPrefix):],
+		MulticodecCode: uint64(keyType),
+		Name:           keyType.String(),
+	}, nil
 }
 
-// Decode converts a DID key string back to key type and raw bytes
-func Decode(didKey string) (KeyType, []byte, error) {
+// DecodeWithBase decodes a DID key like Decode, also returning the multibase
+// encoding of its fingerprint, so that tools can report e.g. "you used
+// base64url". The encoding is set whenever the fingerprint starts with a known
+// multibase prefix, including when decoding fails with ErrExpectedBase58BTC.
+// Otherwise it is zero, which is also the code of the identity encoding.
+func DecodeWithBase(didKey string, opts ...Option) (KeyType, []byte, multibase.Encoding, error) {
+	var encoding multibase.Encoding
+	if fingerprint, ok := strings.CutPrefix(didKey, DIDKeyPrefix); ok && fingerprint != "" {
+		if _, known := multibase.EncodingToStr[multibase.Encoding(fingerprint[0])]; known {
+			encoding = multibase.Encoding(fingerprint[0])
+		}
+	}
+
+	keyType, keyBytes, err := Decode(didKey, opts...)
+	return keyType, keyBytes, encoding, err
+}
+
+// decodeDIDKey strips the DID key prefix and multibase encoding, returning the
+// multicodec-prefixed key bytes
+//
+// The input is checked to be valid UTF-8, ASCII-only without control characters, with
+// a fingerprint length that some supported key type can produce, before any
+// decoding work is done.
+func decodeDIDKey(didKey string) ([]byte, error) {
+	if !utf8.ValidString(didKey) {
+		return nil, ErrInvalidUTF8
+	}
+
+	for i := 0; i < len(didKey); i++ {
+		// NUL and other control characters typically come from mis-decoded binary data
+		if didKey[i] < 0x20 || didKey[i] == 0x7f {
+			return nil, ErrControlCharacterWithContext(i, didKey[i])
+		}
+
+		// DID keys are ASCII-only; look-alikes such as full-width digits usually
+		// come from UI input or Unicode normalization
+		if didKey[i] >= utf8.RuneSelf {
+			char, _ := utf8.DecodeRuneInString(didKey[i:])
+			return nil, ErrNonASCIIWithContext(i, char)
+		}
+	}
+
 	if !strings.HasPrefix(didKey, DIDKeyPrefix) {
-		return 0, nil, ErrInvalidDIDKeyPrefixWithContext(DIDKeyPrefix)
+		return nil, ErrInvalidDIDKeyPrefixWithContext(DIDKeyPrefix)
 	}
 
 	multibaseString := didKey[len(DIDKeyPrefix):]
 	if multibaseString == "" {
-		return 0, nil, ErrEmptyMultibaseString
+		return nil, ErrEmptyMultibaseString
+	}
+
+	// Checked on its own so that oversized input never reaches the base58 decoder,
+	// whose cost grows quadratically with the input length
+	if len(multibaseString) > MaxFingerprintChars {
+		return nil, ErrFingerprintTooLongWithContext(len(multibaseString), MaxFingerprintChars)
+	}
+
+	if len(multibaseString) < minFingerprintLength || len(multibaseString) > maxFingerprintLength {
+		return nil, ErrInvalidFingerprintLengthWithContext(len(multibaseString), minFingerprintLength, maxFingerprintLength)
+	}
+
+	return decodeMultibase(multibaseString)
+}
+
+// minFingerprintLength and maxFingerprintLength bound the length of the
+// multibase fingerprints of all supported key types
+var minFingerprintLength, maxFingerprintLength = func() (int, int) {
+	minLength, maxLength := 0, 0
+	for _, keyType := range supportedKeyTypes {
+		lo, hi := fingerprintLengthRange(keyType)
+		if minLength == 0 || lo < minLength {
+			minLength = lo
+		}
+		maxLength = max(maxLength, hi)
+	}
+	return minLength, maxLength
+}()
+
+// fingerprintLengthRange returns the shortest and longest multibase fingerprint
+// of a supported key type. The base58 length grows with the encoded value, so
+// these are the lengths of the all-zero and all-0xff keys.
+func fingerprintLengthRange(keyType KeyType) (int, int) {
+	size, _ := keySize(keyType)
+	lowest := encodeMulticodec(keyType, make([]byte, size))
+	highest := encodeMulticodec(keyType, bytes.Repeat([]byte{0xff}, size))
+	return 1 + len(encodeBase58BTC(lowest)), 1 + len(encodeBase58BTC(highest))
+}
+
+// DecodeURN decodes a DID key that may be wrapped as a URN, e.g.
+// "urn:did:key:z6Mk...", as some registries store DIDs. Only a single leading
+// "urn:" (case-insensitive, like all URN schemes) is stripped; any other wrapper
+// fails like Decode does. This is a convenience for interoperability: URN-wrapped
+// DIDs are not part of the did:key specification, and Decode rejects them.
+func DecodeURN(didKey string, opts ...Option) (KeyType, []byte, error) {
+	if len(didKey) >= len(urnPrefix) && strings.EqualFold(didKey[:len(urnPrefix)], urnPrefix) {
+		didKey = didKey[len(urnPrefix):]
+	}
+
+	return Decode(didKey, opts...)
+}
+
+// DIDKeyStringLength returns the shortest and longest possible did:key string
+// of a key type, including the "did:key:" prefix, so input can be rejected by
+// length before decoding.
+func DIDKeyStringLength(keyType KeyType) (minLength, maxLength int, err error) {
+	if _, ok := keySize(keyType); !ok {
+		return 0, 0, ErrUnsupportedKeyTypeWithContext(keyType)
+	}
+
+	lo, hi := fingerprintLengthRange(keyType)
+	return len(DIDKeyPrefix) + lo, len(DIDKeyPrefix) + hi, nil
+}
+
+// NormalizeScheme lowercases the case-insensitive "did" URI scheme of a DID key
+// (RFC 3986), so "DID:key:z6Mk..." becomes "did:key:z6Mk...". The method name is
+// case-sensitive and must be exactly "key"; the rest of the string is left
+// untouched and not validated.
+func NormalizeScheme(didKey string) (string, error) {
+	scheme, rest, ok := strings.Cut(didKey, ":")
+	if !ok || !strings.EqualFold(scheme, "did") || !strings.HasPrefix(rest, "key:") {
+		return "", ErrInvalidDIDKeyPrefixWithContext(DIDKeyPrefix)
 	}
 
-	encoding, multicodecBytes, err := multibase.Decode(multibaseString)
+	return "did:" + rest, nil
+}
+
+// encodeMulticodec prefixes key bytes with the varint multicodec code of the key type.
+// Codes are unsigned LEB128 varints, low 7 bits first, so every supported code
+// takes two bytes: 0xed 0x01 for Ed25519 (0xed), 0xe7 0x01 for secp256k1 (0xe7),
+// 0x80 0x24 for P-256 (0x1200) and 0x81 0x24 for P-384 (0x1201). Codes from
+// 0x4000 take three bytes, e.g. 0x80 0x80 0x01 for 0x4000.
+func encodeMulticodec(keyType KeyType, keyBytes []byte) []byte {
+	codecBytes := varint.ToUvarint(uint64(keyType))
+	multicodecBytes := make([]byte, len(codecBytes)+len(keyBytes))
+	copy(multicodecBytes, codecBytes)
+	copy(multicodecBytes[len(codecBytes):], keyBytes)
+	return multicodecBytes
+}
+
+// decodeMulticodec splits multicodec-prefixed key bytes into the key type and
+// key bytes, validating the key
+func decodeMulticodec(multicodecBytes []byte, o options) (KeyType, []byte, error) {
+	keyType, keyBytes, err := splitMulticodec(multicodecBytes)
 	if err != nil {
-		return 0, nil, ErrMultibaseDecodeFailedWithContext(err)
+		return 0, nil, err
 	}
 
-	// DID keys must use base58-btc encoding per specification
-	if encoding != multibase.Base58BTC {
-		return 0, nil, ErrExpectedBase58BTC
+	if err := validateKey(keyType, keyBytes, o); err != nil {
+		return 0, nil, err
 	}
 
+	return keyType, keyBytes, nil
+}
+
+// splitMulticodec splits multicodec-prefixed key bytes into the key type and
+// key bytes without validating the key. Input with no bytes at all fails with
+// ErrEmptyData; input holding only the varint fails with ErrNoKeyDataAfterVarint.
+// Decode rejects fingerprints this short by length before decoding them, so
+// these errors surface from FromMulticodecBytes and Multikey verification
+// methods.
+func splitMulticodec(multicodecBytes []byte) (KeyType, []byte, error) {
 	if len(multicodecBytes) == 0 {
 		return 0, nil, ErrEmptyData
 	}
@@ -69,12 +307,34 @@ func Decode(didKey string) (KeyType, []byte, error) {
 		return 0, nil, ErrNoKeyDataAfterVarint
 	}
 
-	keyType := KeyType(value)
-	keyBytes := multicodecBytes[bytesRead:]
+	return KeyType(value), multicodecBytes[bytesRead:], nil
+}
 
-	if err := validateKeySize(keyType, keyBytes); err != nil {
-		return 0, nil, err
+// decodeMultibase decodes a base58-btc multibase string. Other multibase
+// encodings are rejected with ErrExpectedBase58BTC. Decode failures wrap
+// ErrMultibaseDecodeFailed and, where the cause is known, ErrInvalidBase58Character
+// or ErrUnknownMultibasePrefix.
+func decodeMultibase(multibaseString string) ([]byte, error) {
+	if multibaseString[0] == byte(multibase.Base58BTC) {
+		data, err := decodeBase58BTC(multibaseString[1:])
+		if err != nil {
+			return nil, ErrMultibaseDecodeFailedWithContext(err)
+		}
+		return data, nil
 	}
 
-	return keyType, keyBytes, nil
+	// Not base58-btc: tell apart other valid multibase encodings from garbage.
+	// A base58-btc fingerprint always starts with 'z', so a different leading
+	// character is never part of a base58-btc value.
+	encoding, _, err := multibase.Decode(multibaseString)
+	if errors.Is(err, multibase.ErrUnsupportedEncoding) {
+		prefix, _ := utf8.DecodeRuneInString(multibaseString)
+		return nil, ErrMultibaseDecodeFailedWithContext(ErrUnknownMultibasePrefixWithContext(prefix))
+	}
+	if err != nil {
+		return nil, ErrMultibaseDecodeFailedWithContext(err)
+	}
+
+	// DID keys must use base58-btc encoding per specification
+	return nil, ErrExpectedBase58BTCWithContext(encoding)
 }
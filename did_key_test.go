@@ -3,7 +3,13 @@ package didkey
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/multiformats/go-multibase"
 )
 
 // Test vectors using real DID keys and their corresponding raw bytes
@@ -40,6 +46,14 @@ var testVectors = map[string]struct {
 	},
 }
 
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
 func TestEncode(t *testing.T) {
 	for name, tv := range testVectors {
 		t.Run(name, func(t *testing.T) {
@@ -158,9 +172,15 @@ func TestValidation(t *testing.T) {
 		{
 			name:      "Valid Secp256k1",
 			keyType:   Secp256k1PublicKey,
-			keyBytes:  make([]byte, 33),
+			keyBytes:  mustDecodeHex("03fdd57adec3d438ea237fe46b33ee1e016eda6b585c3e27ea66686c2ea5358479"),
 			shouldErr: false,
 		},
+		{
+			name:      "Invalid Secp256k1 prefix",
+			keyType:   Secp256k1PublicKey,
+			keyBytes:  make([]byte, 33),
+			shouldErr: true,
+		},
 		{
 			name:      "Invalid Secp256k1 size",
 			keyType:   Secp256k1PublicKey,
@@ -268,3 +288,730 @@ func TestSpecificationExamples(t *testing.T) {
 		t.Errorf("Round-trip failed: expected %s, got %s", specDID, reencoded)
 	}
 }
+
+// w3cTestVectors are did:key test vectors from the W3C DID Key specification,
+// covering every supported key type
+var w3cTestVectors = []string{
+	"did:key:z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp",
+	"did:key:z6MkjchhfUsD6mmvni8mCdXHw216Xrm9bQe2mBH1P5RDjVJG",
+	"did:key:z6MknGc3ocHs3zdPiJbnaaqDi58NGb4pk1Sp9WxWufuXSdxf",
+	"did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme",
+	"did:key:zQ3shtxV1FrJfhqE1dvxYRcCknWNjHc3c5X1y3ZSoPDi2aur2",
+	"did:key:zQ3shZc2QzApp2oymGvQbzP8eKheVshBHbU4ZYjeXqwSKEn6N",
+	"did:key:zDnaerDaTF5BXEavCrfRZEk316dpbLsfPDZ3WJ5hRTPFU2169",
+	"did:key:zDnaerx9CtbPJ1q36T5Ln5wYt3MQYeGRG5ehnPAmxcf5mDZpv",
+	"did:key:z82Lm1MpAkeJcix9K8TMiLd5NMAhnwkjjCBeWHXyu3U4oT2MVJJKXkcVBgjGhnLBn2Kaau9",
+	"did:key:z82LkvCwHNreneWpsgPEbV3gu1C6NFJEBg4srfJ5gdxEsMGRJUz2sG9FE42shbn2xkZJh54",
+	"did:key:z3tEFALUKUzzCAvytMHX8X4SnsNsq6T5tC5Zb18oQEt1FqNcJXqJ3AA9umgzA9yoqPBeWA",
+	"did:key:zUC7EK3ZakmukHhuncwkbySmomv3FmrkmS36E4Ks5rsb6VQSRpoCrx6Hb8e2Nk6UvJFSdyw9NK1scFXJp21gNNYFjVWNgaqyGnkyhtagagCpQb5B7tagJu3HDbjQ8h5ypoHjwBb",
+	"did:key:z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F",
+	"did:key:z6LStiZsmxiK4odS4Sb6JThen5QBnhgRYPnLXwY7h2NR4Wyj",
+}
+
+// malformedVariants returns corrupted copies of a DID key: truncated, with a
+// flipped character and with wrong DID method or multibase prefixes
+func malformedVariants(didKey string) []string {
+	fingerprint := didKey[len(DIDKeyPrefix):]
+	flipped := []byte(didKey)
+	flipped[len(flipped)/2] ^= 0x01
+
+	return []string{
+		didKey[:len(didKey)-1],
+		didKey[:len(DIDKeyPrefix)+len(fingerprint)/2],
+		string(flipped),
+		"did:web:" + fingerprint,
+		DIDKeyPrefix + "f" + fingerprint[1:],
+		DIDKeyPrefix + fingerprint[1:],
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	for _, didKey := range w3cTestVectors {
+		f.Add(didKey)
+		for _, variant := range malformedVariants(didKey) {
+			f.Add(variant)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, didKey string) {
+		keyType, keyBytes, err := Decode(didKey)
+		if err != nil {
+			return
+		}
+
+		reencoded, err := Encode(keyType, keyBytes)
+		if err != nil {
+			t.Fatalf("Encode(Decode(%q)) failed: %v", didKey, err)
+		}
+
+		if reencoded != didKey {
+			t.Fatalf("Encode(Decode(%q)) = %q", didKey, reencoded)
+		}
+	})
+}
+
+func TestFuzzSeeds(t *testing.T) {
+	// The seeds must reach the decode branch of every key type
+	decoded := make(map[KeyType]bool)
+	for _, didKey := range w3cTestVectors {
+		keyType, _, err := Decode(didKey)
+		if err != nil {
+			t.Fatalf("Decode(%s) failed: %v", didKey, err)
+		}
+		decoded[keyType] = true
+
+		// A flipped character may still be a valid key of the same type, as
+		// did:key has no checksum; truncations and wrong prefixes never are
+		for _, variant := range malformedVariants(didKey) {
+			if _, _, err := Decode(variant); err == nil && len(variant) != len(didKey) {
+				t.Errorf("Expected malformed variant %q to fail", variant)
+			}
+		}
+	}
+
+	for _, keyType := range supportedKeyTypes {
+		if !decoded[keyType] {
+			t.Errorf("No seed decodes as %s", keyType)
+		}
+	}
+}
+
+// blsPlaceholderKey returns BLS12-381 key bytes that pass validation without
+// being a real key: the compression flag followed by zeros
+func blsPlaceholderKey(size int) []byte {
+	keyBytes := make([]byte, size)
+	keyBytes[0] = blsCompressionFlag
+	return keyBytes
+}
+
+// randomValidKey returns random key bytes that pass validation for the key type.
+// EC keys are found by drawing random x coordinates until one is on the curve.
+func randomValidKey(rng *rand.Rand, keyType KeyType) []byte {
+	sizes := map[KeyType]int{
+		Ed25519PublicKey:    32,
+		X25519PublicKey:     32,
+		Bls12381G1PublicKey: 48,
+		Bls12381G2PublicKey: 96,
+	}
+
+	if size, ok := sizes[keyType]; ok {
+		keyBytes := make([]byte, size)
+		rng.Read(keyBytes)
+		if isBLSKeyType(keyType) {
+			keyBytes[0] = keyBytes[0]&^blsInfinityFlag | blsCompressionFlag
+		}
+		return keyBytes
+	}
+
+	curve, _ := curveFor(keyType)
+	keyBytes := make([]byte, 1+curve.size)
+	for {
+		keyBytes[0] = 0x02 | byte(rng.Intn(2))
+		rng.Read(keyBytes[1:])
+		if _, _, ok := curve.decompress(keyBytes); ok {
+			return keyBytes
+		}
+	}
+}
+
+func TestRoundTripProperty(t *testing.T) {
+	const seed = 20240601
+	const iterations = 200
+	rng := rand.New(rand.NewSource(seed))
+
+	for _, keyType := range supportedKeyTypes {
+		t.Run(keyType.String(), func(t *testing.T) {
+			for i := 0; i < iterations; i++ {
+				keyBytes := randomValidKey(rng, keyType)
+
+				didKey, err := Encode(keyType, keyBytes)
+				if err != nil {
+					t.Fatalf("seed %d: Encode(%s, %x) failed: %v", seed, keyType, keyBytes, err)
+				}
+
+				decodedType, decodedBytes, err := Decode(didKey)
+				if err != nil {
+					t.Fatalf("seed %d: Decode(%s) failed: %v", seed, didKey, err)
+				}
+
+				if decodedType != keyType || !bytes.Equal(decodedBytes, keyBytes) {
+					t.Fatalf("seed %d: Decode(Encode(%s, %x)) = %s, %x", seed, keyType, keyBytes, decodedType, decodedBytes)
+				}
+
+				reencoded, err := Encode(decodedType, decodedBytes)
+				if err != nil {
+					t.Fatalf("seed %d: Encode(%s, %x) failed: %v", seed, decodedType, decodedBytes, err)
+				}
+
+				if reencoded != didKey {
+					t.Fatalf("seed %d: Encode(Decode(%s)) = %s", seed, didKey, reencoded)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeInputChecks(t *testing.T) {
+	valid := testVectors["Ed25519-from-spec"].didKey
+
+	tests := []struct {
+		name   string
+		didKey string
+		err    error
+	}{
+		{name: "invalid UTF-8", didKey: valid[:20] + "\xff" + valid[21:], err: ErrInvalidUTF8},
+		{name: "invalid UTF-8 prefix", didKey: "did:key\xc0:z6Mk", err: ErrInvalidUTF8},
+		{name: "overlong", didKey: valid + strings.Repeat("z", 1<<20), err: ErrFingerprintTooLong},
+		// '0' is not a base58 digit, so only rejecting before decoding yields ErrFingerprintTooLong
+		{name: "over the limit", didKey: DIDKeyPrefix + "z" + strings.Repeat("0", MaxFingerprintChars), err: ErrFingerprintTooLong},
+		{name: "absurdly long", didKey: DIDKeyPrefix + "z" + strings.Repeat("0", 64<<20), err: ErrFingerprintTooLong},
+		{name: "too short", didKey: valid[:len(valid)-20], err: ErrInvalidFingerprintLength},
+		{name: "prefix case", didKey: "DID:key:" + valid[len(DIDKeyPrefix):], err: ErrInvalidDIDKeyPrefix},
+		{name: "embedded NUL", didKey: valid[:20] + "\x00" + valid[21:], err: ErrControlCharacter},
+		{name: "embedded escape", didKey: valid[:30] + "\x1b" + valid[31:], err: ErrControlCharacter},
+		{name: "trailing newline", didKey: valid + "\n", err: ErrControlCharacter},
+		{name: "NUL in prefix", didKey: "did:\x00ey:" + valid[len(DIDKeyPrefix):], err: ErrControlCharacter},
+		// U+FF16 FULLWIDTH DIGIT SIX looks like the '6' of z6Mk
+		{name: "full-width digit", didKey: DIDKeyPrefix + "z\uff16" + valid[len(DIDKeyPrefix)+2:], err: ErrNonASCII},
+		{name: "non-ASCII prefix", didKey: "did:kéy:" + valid[len(DIDKeyPrefix):], err: ErrNonASCII},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Decode(tt.didKey); !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("non-ASCII error names the character", func(t *testing.T) {
+		_, _, err := Decode(DIDKeyPrefix + "z\uff16" + valid[len(DIDKeyPrefix)+2:])
+		if err == nil || !strings.Contains(err.Error(), "U+FF16") || !strings.Contains(err.Error(), "index 9") {
+			t.Errorf("Expected error naming U+FF16 at index 9, got %v", err)
+		}
+	})
+
+	t.Run("bounds cover every key type", func(t *testing.T) {
+		if minFingerprintLength != 48 || maxFingerprintLength != 135 {
+			t.Errorf("Expected fingerprint lengths 48 to 135, got %d to %d", minFingerprintLength, maxFingerprintLength)
+		}
+		if MaxFingerprintChars != maxFingerprintLength {
+			t.Errorf("Expected MaxFingerprintChars to be %d, got %d", maxFingerprintLength, MaxFingerprintChars)
+		}
+
+		rng := rand.New(rand.NewSource(3))
+		for _, keyType := range supportedKeyTypes {
+			lo, hi := fingerprintLengthRange(keyType)
+			for i := 0; i < 50; i++ {
+				didKey, err := Encode(keyType, randomValidKey(rng, keyType))
+				if err != nil {
+					t.Fatalf("Encode failed: %v", err)
+				}
+
+				if n := len(didKey) - len(DIDKeyPrefix); n < lo || n > hi {
+					t.Fatalf("%s fingerprint length %d outside [%d, %d]", keyType, n, lo, hi)
+				}
+			}
+		}
+	})
+}
+
+func TestDecodeResult(t *testing.T) {
+	tv := testVectors["Ed25519-from-spec"]
+
+	result, err := DecodeResult(tv.didKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.KeyType != Ed25519PublicKey {
+		t.Errorf("Expected key type %s, got %s", Ed25519PublicKey, result.KeyType)
+	}
+
+	if !bytes.Equal(result.KeyBytes, mustDecodeHex(tv.keyHex)) {
+		t.Errorf("Expected key bytes %s, got %x", tv.keyHex, result.KeyBytes)
+	}
+
+	if result.Fingerprint != "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK" {
+		t.Errorf("Unexpected fingerprint %s", result.Fingerprint)
+	}
+
+	if result.MulticodecCode != 0xed {
+		t.Errorf("Expected multicodec code 0xed, got %#x", result.MulticodecCode)
+	}
+
+	if result.Name != "ed25519-pub" {
+		t.Errorf("Expected name ed25519-pub, got %s", result.Name)
+	}
+
+	if _, err := DecodeResult("did:web:example.com"); !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+		t.Errorf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+	}
+}
+
+func TestDecodeExpecting(t *testing.T) {
+	tv := testVectors["Ed25519-from-spec"]
+
+	keyBytes, err := DecodeExpecting(tv.didKey, Ed25519PublicKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(keyBytes, mustDecodeHex(tv.keyHex)) {
+		t.Errorf("Expected key bytes %s, got %x", tv.keyHex, keyBytes)
+	}
+
+	_, err = DecodeExpecting(tv.didKey, P256PublicKey)
+	if !errors.Is(err, ErrKeyTypeMismatch) {
+		t.Fatalf("Expected ErrKeyTypeMismatch, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "expected p256-pub, got ed25519-pub") {
+		t.Errorf("Expected the error to name both key types, got %v", err)
+	}
+
+	if _, err := DecodeExpecting("did:web:example.com", Ed25519PublicKey); !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+		t.Errorf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+	}
+}
+
+func TestEncodeWithCodec(t *testing.T) {
+	// 0x1337 is not a registered multicodec code
+	const codec = 0x1337
+	keyBytes := []byte{1, 2, 3}
+
+	didKey, err := EncodeWithCodec(codec, keyBytes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := decodeMultibase(didKey[len(DIDKeyPrefix):])
+	if err != nil {
+		t.Fatalf("Failed to decode multibase: %v", err)
+	}
+
+	if expected := []byte{0xb7, 0x26, 1, 2, 3}; !bytes.Equal(data, expected) {
+		t.Errorf("Expected multicodec bytes %x, got %x", expected, data)
+	}
+
+	if _, _, err := Decode(didKey); err == nil {
+		t.Errorf("Expected Decode to reject the unknown code")
+	}
+
+	t.Run("empty key bytes", func(t *testing.T) {
+		if _, err := EncodeWithCodec(codec, nil); !errors.Is(err, ErrEmptyKeyBytes) {
+			t.Errorf("Expected ErrEmptyKeyBytes, got %v", err)
+		}
+	})
+
+	t.Run("known codes are validated", func(t *testing.T) {
+		tv := testVectors["Ed25519-from-spec"]
+		didKey, err := EncodeWithCodec(uint64(Ed25519PublicKey), mustDecodeHex(tv.keyHex))
+		if err != nil || didKey != tv.didKey {
+			t.Errorf("Expected %s, got %s, %v", tv.didKey, didKey, err)
+		}
+
+		if _, err := EncodeWithCodec(uint64(Ed25519PublicKey), keyBytes); !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected ErrInvalidKeySize, got %v", err)
+		}
+	})
+}
+
+func TestDecodeWithBase(t *testing.T) {
+	dk, err := FromBytes(P384PublicKey, mustDecodeHex(p384GeneratorHex))
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+
+	t.Run("base58-btc", func(t *testing.T) {
+		keyType, keyBytes, encoding, err := DecodeWithBase(dk.String())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if keyType != P384PublicKey || !bytes.Equal(keyBytes, dk.Bytes()) || encoding != multibase.Base58BTC {
+			t.Errorf("Unexpected result %s %x %c", keyType, keyBytes, encoding)
+		}
+	})
+
+	t.Run("base64url", func(t *testing.T) {
+		encoded, err := multibase.Encode(multibase.Base64url, encodeMulticodec(dk.KeyType(), dk.Bytes()))
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+
+		_, _, encoding, err := DecodeWithBase(DIDKeyPrefix + encoded)
+		if !errors.Is(err, ErrExpectedBase58BTC) {
+			t.Errorf("Expected ErrExpectedBase58BTC, got %v", err)
+		}
+		if encoding != multibase.Base64url {
+			t.Errorf("Expected encoding 'u', got %q", rune(encoding))
+		}
+	})
+
+	t.Run("unknown prefix", func(t *testing.T) {
+		_, _, encoding, err := DecodeWithBase(DIDKeyPrefix + "!" + dk.Fingerprint()[1:])
+		if !errors.Is(err, ErrUnknownMultibasePrefix) {
+			t.Errorf("Expected ErrUnknownMultibasePrefix, got %v", err)
+		}
+		if encoding != 0 {
+			t.Errorf("Expected no encoding, got %q", rune(encoding))
+		}
+	})
+}
+
+func TestMulticodecVarints(t *testing.T) {
+	// Expected varints: the low 7 bits of the code with the continuation bit
+	// set, then the remaining bits: 0x1200 is 0x24<<7 | 0x00, giving 0x80 0x24.
+	tests := []struct {
+		keyType KeyType
+		keyHex  string
+		varint  []byte
+	}{
+		{keyType: Ed25519PublicKey, keyHex: testVectors["Ed25519-from-spec"].keyHex, varint: []byte{0xed, 0x01}},
+		{keyType: Secp256k1PublicKey, keyHex: testVectors["Secp256k1-test"].keyHex, varint: []byte{0xe7, 0x01}},
+		{keyType: P256PublicKey, keyHex: testVectors["P-256-test"].keyHex, varint: []byte{0x80, 0x24}},
+		{keyType: P384PublicKey, keyHex: p384GeneratorHex, varint: []byte{0x81, 0x24}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyType.String(), func(t *testing.T) {
+			keyBytes := mustDecodeHex(tt.keyHex)
+
+			didKey, err := Encode(tt.keyType, keyBytes)
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+
+			data, err := decodeMultibase(didKey[len(DIDKeyPrefix):])
+			if err != nil {
+				t.Fatalf("Failed to decode multibase: %v", err)
+			}
+			if expected := append(bytes.Clone(tt.varint), keyBytes...); !bytes.Equal(data, expected) {
+				t.Errorf("Expected multicodec bytes %x, got %x", expected, data)
+			}
+
+			keyType, decoded, err := Decode(didKey)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if keyType != tt.keyType || !bytes.Equal(decoded, keyBytes) {
+				t.Errorf("Expected %s %x, got %s %x", tt.keyType, keyBytes, keyType, decoded)
+			}
+		})
+	}
+}
+
+func TestLeadingZeroCoordinate(t *testing.T) {
+	// Compressed keys whose x-coordinate starts with zero bytes: x = 1 is on
+	// secp256k1 and x = 5 on P-256. The multicodec varint comes first and never
+	// starts with a zero byte, so the base58 encoding has no leading '1', and the
+	// zero bytes inside the value must survive the round-trip.
+	tests := []struct {
+		keyType KeyType
+		keyHex  string
+		didKey  string
+	}{
+		{
+			keyType: Secp256k1PublicKey,
+			keyHex:  "020000000000000000000000000000000000000000000000000000000000000001",
+			didKey:  "did:key:zQ3shMQnkqiyfujhRPGFFqSEeD2yV9kUcmyBiu2fT2BXfFPMJ",
+		},
+		{
+			keyType: P256PublicKey,
+			keyHex:  "020000000000000000000000000000000000000000000000000000000000000005",
+			didKey:  "did:key:zDnaeQRy3dcKsKa1zmKtVKsTy3m2HYoQnFnfKuxD6HfSTQgYk",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyType.String(), func(t *testing.T) {
+			keyBytes := mustDecodeHex(tt.keyHex)
+
+			didKey, err := Encode(tt.keyType, keyBytes)
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			if didKey != tt.didKey {
+				t.Errorf("Expected %s, got %s", tt.didKey, didKey)
+			}
+
+			keyType, decoded, err := Decode(didKey)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if keyType != tt.keyType || !bytes.Equal(decoded, keyBytes) {
+				t.Errorf("Expected %s %x, got %s %x", tt.keyType, keyBytes, keyType, decoded)
+			}
+		})
+	}
+}
+
+func TestMultibaseCrossValidation(t *testing.T) {
+	// The internal base58-btc codec and go-multibase must agree on every key
+	// type, so a dependency update cannot silently change what Decode accepts
+	rng := rand.New(rand.NewSource(7))
+	for _, keyType := range supportedKeyTypes {
+		t.Run(keyType.String(), func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				keyBytes := randomValidKey(rng, keyType)
+				data := encodeMulticodec(keyType, keyBytes)
+
+				didKey, err := Encode(keyType, keyBytes)
+				if err != nil {
+					t.Fatalf("Encode failed: %v", err)
+				}
+
+				encoding, decoded, err := multibase.Decode(didKey[len(DIDKeyPrefix):])
+				if err != nil {
+					t.Fatalf("go-multibase failed to decode %s: %v", didKey, err)
+				}
+				if encoding != multibase.Base58BTC || !bytes.Equal(decoded, data) {
+					t.Fatalf("go-multibase decoded %s as %c %x, expected %x", didKey, encoding, decoded, data)
+				}
+
+				encoded, err := multibase.Encode(multibase.Base58BTC, data)
+				if err != nil {
+					t.Fatalf("go-multibase failed to encode: %v", err)
+				}
+				if DIDKeyPrefix+encoded != didKey {
+					t.Fatalf("go-multibase encoded %x as %s, expected %s", data, encoded, didKey)
+				}
+
+				gotType, gotBytes, err := Decode(DIDKeyPrefix + encoded)
+				if err != nil {
+					t.Fatalf("Decode failed: %v", err)
+				}
+				if gotType != keyType || !bytes.Equal(gotBytes, keyBytes) {
+					t.Fatalf("Decoded %s as %s %x, expected %x", encoded, gotType, gotBytes, keyBytes)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeOtherMultibaseEncodings(t *testing.T) {
+	// A P-384 key is long enough to pass the fingerprint length check in every base
+	dk, err := FromBytes(P384PublicKey, mustDecodeHex(p384GeneratorHex))
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+	data := encodeMulticodec(dk.KeyType(), dk.Bytes())
+
+	tests := []struct {
+		encoding multibase.Encoding
+		name     string
+	}{
+		{encoding: multibase.Base16, name: "base16 ('f')"},
+		{encoding: multibase.Base32, name: "base32 ('b')"},
+		{encoding: multibase.Base64, name: "base64 ('m')"},
+		{encoding: multibase.Base64url, name: "base64url ('u')"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := multibase.Encode(tt.encoding, data)
+			if err != nil {
+				t.Fatalf("Failed to encode: %v", err)
+			}
+
+			_, _, err = Decode(DIDKeyPrefix + encoded)
+			if !errors.Is(err, ErrExpectedBase58BTC) {
+				t.Fatalf("Expected ErrExpectedBase58BTC, got %v", err)
+			}
+
+			if !strings.Contains(err.Error(), tt.name) {
+				t.Errorf("Expected error to name %s, got %q", tt.name, err.Error())
+			}
+		})
+	}
+}
+
+func TestDecodeMultibaseErrors(t *testing.T) {
+	fingerprint := "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	tests := []struct {
+		name        string
+		didKey      string
+		expectedErr error
+		message     string
+	}{
+		{
+			name:        "invalid base58 character",
+			didKey:      DIDKeyPrefix + "z6Mkha0gBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			expectedErr: ErrInvalidBase58Character,
+			message:     `invalid base58 character '0'`,
+		},
+		{
+			name:        "unknown prefix",
+			didKey:      DIDKeyPrefix + "!" + fingerprint[1:],
+			expectedErr: ErrUnknownMultibasePrefix,
+			message:     `unknown multibase prefix '!'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Decode(tt.didKey)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+			}
+			if !errors.Is(err, ErrMultibaseDecodeFailed) {
+				t.Errorf("Expected error to also wrap ErrMultibaseDecodeFailed, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Expected error to contain %q, got %q", tt.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestDIDKeyStringLength(t *testing.T) {
+	vectors := slices.Clone(w3cTestVectors)
+	for _, tv := range testVectors {
+		vectors = append(vectors, tv.didKey)
+	}
+
+	seen := make(map[KeyType]bool)
+	for _, didKey := range vectors {
+		keyType, _, err := Decode(didKey)
+		if err != nil {
+			t.Fatalf("Decode(%s) failed: %v", didKey, err)
+		}
+		seen[keyType] = true
+
+		minLength, maxLength, err := DIDKeyStringLength(keyType)
+		if err != nil {
+			t.Fatalf("DIDKeyStringLength(%s) failed: %v", keyType, err)
+		}
+		if len(didKey) < minLength || len(didKey) > maxLength {
+			t.Errorf("%s length %d outside [%d, %d]", didKey, len(didKey), minLength, maxLength)
+		}
+	}
+
+	if len(seen) != len(supportedKeyTypes) {
+		t.Errorf("Expected vectors for all %d key types, got %d", len(supportedKeyTypes), len(seen))
+	}
+
+	if minLength, maxLength, _ := DIDKeyStringLength(Ed25519PublicKey); minLength != 56 || maxLength != 56 {
+		t.Errorf("Expected Ed25519 did:key length 56, got %d to %d", minLength, maxLength)
+	}
+
+	if _, _, err := DIDKeyStringLength(KeyType(0x99)); !errors.Is(err, ErrUnsupportedKeyType) {
+		t.Errorf("Expected ErrUnsupportedKeyType, got %v", err)
+	}
+}
+
+func TestDecodeURN(t *testing.T) {
+	tv := testVectors["Ed25519-from-spec"]
+
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr error
+	}{
+		{name: "urn wrapped", input: "urn:" + tv.didKey},
+		{name: "uppercase urn", input: "URN:" + tv.didKey},
+		{name: "bare DID key", input: tv.didKey},
+		{name: "double urn", input: "urn:urn:" + tv.didKey, expectedErr: ErrInvalidDIDKeyPrefix},
+		{name: "other urn namespace", input: "urn:uuid:" + tv.didKey, expectedErr: ErrInvalidDIDKeyPrefix},
+		{name: "other wrapper", input: "uri:" + tv.didKey, expectedErr: ErrInvalidDIDKeyPrefix},
+		{name: "urn only", input: "urn:", expectedErr: ErrInvalidDIDKeyPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyType, keyBytes, err := DecodeURN(tt.input)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeURN failed: %v", err)
+			}
+			if keyType != tv.keyType || hex.EncodeToString(keyBytes) != tv.keyHex {
+				t.Errorf("Expected %s %s, got %s %x", tv.keyType, tv.keyHex, keyType, keyBytes)
+			}
+		})
+	}
+
+	if _, _, err := Decode("urn:" + tv.didKey); !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+		t.Errorf("Expected Decode to reject urn-wrapped DID key, got %v", err)
+	}
+}
+
+func TestDecodeShortPayloads(t *testing.T) {
+	tests := []struct {
+		name        string
+		fingerprint string
+		expectedErr error
+	}{
+		// base58 of zero bytes is the empty string
+		{name: "zero bytes", fingerprint: "z" + encodeBase58BTC(nil), expectedErr: ErrEmptyData},
+		{name: "varint only", fingerprint: "z" + encodeBase58BTC([]byte{0xed, 0x01}), expectedErr: ErrNoKeyDataAfterVarint},
+		// A leading '1' is a zero byte: a one-byte varint, not empty data
+		{name: "single zero byte", fingerprint: "z1", expectedErr: ErrNoKeyDataAfterVarint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			multicodecBytes, err := decodeMultibase(tt.fingerprint)
+			if err != nil {
+				t.Fatalf("decodeMultibase failed: %v", err)
+			}
+
+			if _, err := FromMulticodecBytes(multicodecBytes); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("FromMulticodecBytes: expected %v, got %v", tt.expectedErr, err)
+			}
+
+			vm := VerificationMethod{Type: MultikeyType, PublicKeyMultibase: tt.fingerprint}
+			if _, err := vm.PublicKey(); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("PublicKey: expected %v, got %v", tt.expectedErr, err)
+			}
+
+			// Decode rejects these fingerprints by length before decoding
+			if _, _, err := Decode(DIDKeyPrefix + tt.fingerprint); !errors.Is(err, ErrInvalidFingerprintLength) {
+				t.Errorf("Decode: expected ErrInvalidFingerprintLength, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeScheme(t *testing.T) {
+	fingerprint := "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	tests := []struct {
+		input    string
+		expected string
+		err      error
+	}{
+		{input: "did:key:" + fingerprint, expected: "did:key:" + fingerprint},
+		{input: "DID:key:" + fingerprint, expected: "did:key:" + fingerprint},
+		{input: "Did:key:" + fingerprint, expected: "did:key:" + fingerprint},
+		{input: "DID:key:Z6MK", expected: "did:key:Z6MK"},
+		{input: "did:KEY:" + fingerprint, err: ErrInvalidDIDKeyPrefix},
+		{input: "DID:Key:" + fingerprint, err: ErrInvalidDIDKeyPrefix},
+		{input: "did:web:example.com", err: ErrInvalidDIDKeyPrefix},
+		{input: "urn:key:" + fingerprint, err: ErrInvalidDIDKeyPrefix},
+		{input: "did", err: ErrInvalidDIDKeyPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			normalized, err := NormalizeScheme(tt.input)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("Expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if normalized != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, normalized)
+			}
+		})
+	}
+}
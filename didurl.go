@@ -0,0 +1,116 @@
+package didkey
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// DIDURL is a DID key URL split into its components:
+//
+//	did:key:<fingerprint>[/path][?query][#fragment]
+type DIDURL struct {
+	DID      string // The DID key, e.g. did:key:z6Mk...
+	Path     string // Path including the leading '/', or empty
+	Query    string // Query without the leading '?', or empty
+	Fragment string // Fragment without the leading '#', or empty
+}
+
+// ParseDIDURL parses a DID URL whose DID is a DID key. The DID is validated
+// with Decode and the query must be well-formed; see QueryParams.
+//
+// A path consisting of a single '/' (as appended by some URL builders) is
+// treated as an empty path, so "did:key:z6Mk.../" parses to the bare DID.
+// Longer paths, including "//", are kept as-is.
+//
+// Matrix parameters on the DID, as in did:key:z6Mk...;versionId=1, predate
+// DID Core and return ErrMatrixParametersUnsupported: a did:key is generated
+// from its key alone, so there is nothing for them to select. DID URL
+// parameters belong in the query, e.g. did:key:z6Mk...?versionId=1.
+func ParseDIDURL(didURL string, opts ...Option) (*DIDURL, error) {
+	u, _, _, err := parseDIDURL(didURL, opts...)
+	return u, err
+}
+
+// FromURL decodes the DID key of a parsed URL. did:key URLs are opaque URIs,
+// so url.Parse puts "did" in Scheme and "key:<fingerprint>" in Opaque, followed
+// by any path; the query and fragment go to RawQuery and Fragment. Only the
+// DID is decoded: the path, query and fragment (e.g. a verification method
+// reference) are left to the caller to read from the URL.
+func FromURL(u *url.URL, opts ...Option) (*DIDKey, error) {
+	if u == nil || !strings.EqualFold(u.Scheme, "did") || u.Opaque == "" {
+		return nil, ErrInvalidDIDURLWithContext(errors.New("expected an opaque did: URL"))
+	}
+
+	_, keyType, keyBytes, err := parseDIDURL("did:"+u.Opaque, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DIDKey{keyType: keyType, keyBytes: keyBytes}, nil
+}
+
+// QueryParams returns the parsed query parameters of the DID URL. Repeated
+// parameters keep all their values, in order.
+func (u *DIDURL) QueryParams() url.Values {
+	// The query was validated by ParseDIDURL
+	values, _ := url.ParseQuery(u.Query)
+	return values
+}
+
+// parseDIDURL parses a DID key URL, also returning the decoded key
+func parseDIDURL(didURL string, opts ...Option) (*DIDURL, KeyType, []byte, error) {
+	u := &DIDURL{}
+	rest := didURL
+
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		u.Fragment = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		u.Query = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		u.Path = rest[i:]
+		rest = rest[:i]
+	}
+
+	if u.Path == "/" {
+		u.Path = ""
+	}
+
+	if i := strings.IndexByte(rest, ';'); i >= 0 {
+		return nil, 0, nil, ErrMatrixParametersUnsupportedWithContext(rest[i+1:])
+	}
+
+	if _, err := url.ParseQuery(u.Query); err != nil {
+		return nil, 0, nil, ErrInvalidDIDURLWithContext(err)
+	}
+
+	keyType, keyBytes, err := Decode(rest, opts...)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	u.DID = rest
+
+	return u, keyType, keyBytes, nil
+}
+
+// String reassembles the DID URL
+func (u *DIDURL) String() string {
+	var sb strings.Builder
+	sb.WriteString(u.DID)
+	sb.WriteString(u.Path)
+	if u.Query != "" {
+		sb.WriteByte('?')
+		sb.WriteString(u.Query)
+	}
+	if u.Fragment != "" {
+		sb.WriteByte('#')
+		sb.WriteString(u.Fragment)
+	}
+	return sb.String()
+}
@@ -0,0 +1,212 @@
+package didkey
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestParseDIDURL(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	tests := []struct {
+		name     string
+		input    string
+		expected DIDURL
+	}{
+		{
+			name:     "bare DID",
+			input:    did,
+			expected: DIDURL{DID: did},
+		},
+		{
+			name:     "trailing slash",
+			input:    did + "/",
+			expected: DIDURL{DID: did},
+		},
+		{
+			name:     "double trailing slash",
+			input:    did + "//",
+			expected: DIDURL{DID: did, Path: "//"},
+		},
+		{
+			name:     "trailing slash with fragment",
+			input:    did + "/#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			expected: DIDURL{DID: did, Fragment: "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"},
+		},
+		{
+			name:     "path, query and fragment",
+			input:    did + "/some/path?service=files#key-1",
+			expected: DIDURL{DID: did, Path: "/some/path", Query: "service=files", Fragment: "key-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := ParseDIDURL(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if *u != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, *u)
+			}
+		})
+	}
+
+	t.Run("invalid DID", func(t *testing.T) {
+		_, err := ParseDIDURL("did:web:example.com/")
+		if !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+			t.Errorf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+		}
+	})
+}
+
+func TestDecodeRejectsTrailingSlash(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	for _, input := range []string{did + "/", did + "//"} {
+		if _, _, err := Decode(input); err == nil {
+			t.Errorf("Expected error for %s", input)
+		}
+	}
+}
+
+func TestDIDURLQueryParams(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	u, err := ParseDIDURL(did + "?service=a&service=b&relativeRef=%2Fx#frag")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	params := u.QueryParams()
+	if !slices.Equal(params["service"], []string{"a", "b"}) {
+		t.Errorf("Expected service values [a b], got %v", params["service"])
+	}
+
+	if params.Get("relativeRef") != "/x" {
+		t.Errorf("Expected relativeRef /x, got %q", params.Get("relativeRef"))
+	}
+
+	if u.Fragment != "frag" {
+		t.Errorf("Expected fragment frag, got %q", u.Fragment)
+	}
+
+	if len(params) != 2 {
+		t.Errorf("Expected 2 parameters, got %v", params)
+	}
+
+	t.Run("no query", func(t *testing.T) {
+		u, err := ParseDIDURL(did)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(u.QueryParams()) != 0 {
+			t.Errorf("Expected no parameters, got %v", u.QueryParams())
+		}
+	})
+
+	t.Run("malformed query", func(t *testing.T) {
+		_, err := ParseDIDURL(did + "?service=%zz")
+		if !errors.Is(err, ErrInvalidDIDURL) {
+			t.Errorf("Expected ErrInvalidDIDURL, got %v", err)
+		}
+	})
+}
+
+func TestDIDURLMatrixParameters(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	for _, input := range []string{
+		did + ";versionId=1",
+		did + ";versionId=1;service=files",
+		did + ";versionId=1/some/path#key-1",
+		did + ";",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseDIDURL(input)
+			if !errors.Is(err, ErrMatrixParametersUnsupported) {
+				t.Errorf("Expected ErrMatrixParametersUnsupported, got %v", err)
+			}
+			if !errors.Is(err, ErrInvalidDIDURL) {
+				t.Errorf("Expected ErrInvalidDIDURL, got %v", err)
+			}
+		})
+	}
+
+	t.Run("semicolon in path", func(t *testing.T) {
+		u, err := ParseDIDURL(did + "/a;b")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if expected := (DIDURL{DID: did, Path: "/a;b"}); *u != expected {
+			t.Errorf("Expected %+v, got %+v", expected, *u)
+		}
+	})
+}
+
+func TestFromURL(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	u, err := url.Parse(did + "#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK")
+	if err != nil {
+		t.Fatalf("url.Parse failed: %v", err)
+	}
+
+	if u.Opaque != "key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK" {
+		t.Fatalf("Expected an opaque URL, got %+v", *u)
+	}
+
+	dk, err := FromURL(u)
+	if err != nil {
+		t.Fatalf("FromURL failed: %v", err)
+	}
+
+	if dk.String() != did {
+		t.Errorf("Expected %s, got %s", did, dk)
+	}
+
+	if u.Fragment != dk.Fingerprint() {
+		t.Errorf("Expected fragment %s, got %s", dk.Fingerprint(), u.Fragment)
+	}
+
+	t.Run("path and query", func(t *testing.T) {
+		u, err := url.Parse(did + "/some/path?service=files")
+		if err != nil {
+			t.Fatalf("url.Parse failed: %v", err)
+		}
+
+		dk, err := FromURL(u)
+		if err != nil {
+			t.Fatalf("FromURL failed: %v", err)
+		}
+
+		if dk.String() != did {
+			t.Errorf("Expected %s, got %s", did, dk)
+		}
+	})
+
+	invalid := []struct {
+		name     string
+		u        *url.URL
+		expected error
+	}{
+		{name: "nil", u: nil, expected: ErrInvalidDIDURL},
+		{name: "not a DID", u: &url.URL{Scheme: "https", Host: "example.com"}, expected: ErrInvalidDIDURL},
+		{name: "not opaque", u: &url.URL{Scheme: "did", Path: "key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"}, expected: ErrInvalidDIDURL},
+		{name: "other method", u: &url.URL{Scheme: "did", Opaque: "web:example.com"}, expected: ErrInvalidDIDKeyPrefix},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromURL(tt.u)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
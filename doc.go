@@ -51,8 +51,8 @@
 //   - Ed25519: 32-byte signature keys
 //   - X25519: 32-byte key agreement keys
 //   - secp256k1: 33-byte compressed public keys
-//   - BLS12-381 G1: 48-byte keys
-//   - BLS12-381 G2: 96-byte keys
+//   - BLS12-381 G1: 48-byte compressed public keys
+//   - BLS12-381 G2: 96-byte compressed public keys
 //   - P-256: 33-byte compressed public keys
 //   - P-384: 49-byte compressed public keys
 //
@@ -62,6 +62,10 @@
 // It is recommended for short-term interactions and should not be used for long-term identity
 // management without appropriate key protection mechanisms.
 //
+// Encode and Decode check that secp256k1, P-256 and P-384 keys are valid points on their
+// curve. The check can be turned off with WithCurveValidation(false) for did:keys from a
+// trusted source, at the cost of letting malformed or malicious points through.
+//
 // # Reference
 //
 // This implementation follows the W3C DID Key specification:
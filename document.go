@@ -0,0 +1,545 @@
+package didkey
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Verification relationship names used in DID Documents
+const (
+	Authentication       = "authentication"
+	AssertionMethod      = "assertionMethod"
+	CapabilityDelegation = "capabilityDelegation"
+	CapabilityInvocation = "capabilityInvocation"
+	KeyAgreement         = "keyAgreement"
+)
+
+// Verification method types
+const (
+	// MultikeyType is the verification method type used in resolved DID Documents
+	MultikeyType = "Multikey"
+
+	JSONWebKey2020Type             = "JsonWebKey2020"
+	Ed25519VerificationKey2018Type = "Ed25519VerificationKey2018"
+	X25519KeyAgreementKey2019Type  = "X25519KeyAgreementKey2019"
+)
+
+// JSON-LD contexts
+const (
+	didContext                        = "https://www.w3.org/ns/did/v1"
+	multikeyContext                   = "https://w3id.org/security/multikey/v1"
+	jsonWebKey2020Context             = "https://w3id.org/security/suites/jws-2020/v1"
+	ed25519VerificationKey2018Context = "https://w3id.org/security/suites/ed25519-2018/v1"
+	x25519KeyAgreementKey2019Context  = "https://w3id.org/security/suites/x25519-2019/v1"
+)
+
+// Document is a DID Document resolved from a DID key. Its JSON encoding is
+// deterministic: members follow the struct field order, and @context entries,
+// verification methods and relationships follow the order of the DID Key
+// specification, so a DID key always marshals to the same bytes.
+type Document struct {
+	Context              []string             `json:"@context"`
+	ID                   string               `json:"id"`
+	AlsoKnownAs          []string             `json:"alsoKnownAs,omitempty"`
+	VerificationMethod   []VerificationMethod `json:"verificationMethod"`
+	Authentication       []string             `json:"authentication,omitempty"`
+	AssertionMethod      []string             `json:"assertionMethod,omitempty"`
+	CapabilityDelegation []string             `json:"capabilityDelegation,omitempty"`
+	CapabilityInvocation []string             `json:"capabilityInvocation,omitempty"`
+	KeyAgreement         []string             `json:"keyAgreement,omitempty"`
+	Service              []Service            `json:"service,omitempty"`
+}
+
+// VerificationMethod is a public key entry of a DID Document
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase,omitempty"`
+	PublicKeyJwk       *JWK   `json:"publicKeyJwk,omitempty"`
+	PublicKeyBase58    string `json:"publicKeyBase58,omitempty"`
+}
+
+// Service is a service entry of a DID Document. DID keys have no services of
+// their own; they are added by callers that publish documents with services.
+type Service struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// VerificationRelationships returns the verification relationships the DID Key
+// specification populates for a key type. For Ed25519 this includes keyAgreement,
+// which is served by the derived X25519 key rather than the Ed25519 key itself.
+// It returns nil for unsupported key types.
+func VerificationRelationships(keyType KeyType) []string {
+	meta, ok := keyTypeMetadata[uint64(keyType)]
+	if !ok {
+		return nil
+	}
+	return slices.Clone(meta.relationships)
+}
+
+// ContextsForKeyType returns the ordered JSON-LD @context URIs of a DID Document
+// for a key of the given type represented with the given verification method
+// type. For Ed25519VerificationKey2018 this includes the X25519KeyAgreementKey2019
+// context used by the derived key agreement key. It returns nil for key types
+// the verification method type cannot represent.
+func ContextsForKeyType(keyType KeyType, vmType string) []string {
+	if VerificationRelationships(keyType) == nil {
+		return nil
+	}
+
+	switch vmType {
+	case MultikeyType:
+		return []string{didContext, multikeyContext}
+	case JSONWebKey2020Type:
+		if keyType == Bls12381G1PublicKey || keyType == Bls12381G2PublicKey {
+			return nil
+		}
+		return []string{didContext, jsonWebKey2020Context}
+	case Ed25519VerificationKey2018Type:
+		if keyType != Ed25519PublicKey {
+			return nil
+		}
+		return []string{didContext, ed25519VerificationKey2018Context, x25519KeyAgreementKey2019Context}
+	case X25519KeyAgreementKey2019Type:
+		if keyType != X25519PublicKey {
+			return nil
+		}
+		return []string{didContext, x25519KeyAgreementKey2019Context}
+	default:
+		return nil
+	}
+}
+
+// ResolveDocument resolves a DID key, or the DID of a DID key URL, to its DID Document.
+// Errors are returned as *ResolutionError.
+//
+// DID keys are generated from the key alone and never change, so the versionId
+// and versionTime DID parameters are meaningless; DID URLs carrying them fail
+// with a notFound error rather than silently returning the only document.
+func ResolveDocument(didKey string, opts ...Option) (*Document, error) {
+	u, keyType, keyBytes, err := parseDIDURL(didKey, opts...)
+	if errors.Is(err, ErrInvalidDIDURL) {
+		return nil, &ResolutionError{Code: ResolutionInvalidDIDURL, Err: err}
+	}
+	if err != nil {
+		return nil, &ResolutionError{Code: ResolutionInvalidDID, Err: err}
+	}
+
+	query := u.QueryParams()
+	for _, param := range []string{"versionId", "versionTime"} {
+		if query.Has(param) {
+			return nil, &ResolutionError{Code: ResolutionNotFound, Err: ErrVersioningUnsupportedWithContext(param)}
+		}
+	}
+
+	doc, err := newDocument(u.DID, keyType, keyBytes, applyOptions(opts))
+	if err != nil {
+		return nil, &ResolutionError{Code: ResolutionInvalidPublicKey, Err: err}
+	}
+	return doc, nil
+}
+
+// EncodeWithDocument encodes key bytes as Encode does and returns the DID key
+// together with its DID Document, as ResolveDocument would resolve it. The
+// document is built from the validated key bytes, without decoding the DID key
+// again.
+func EncodeWithDocument(keyType KeyType, keyBytes []byte, opts ...Option) (string, *Document, error) {
+	dk, err := FromBytes(keyType, keyBytes, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	didKey := dk.String()
+	doc, err := newDocument(didKey, dk.keyType, dk.keyBytes, applyOptions(opts))
+	if err != nil {
+		return "", nil, err
+	}
+	return didKey, doc, nil
+}
+
+// newDocument builds the DID Document of a DID key from its decoded key
+func newDocument(didKey string, keyType KeyType, keyBytes []byte, o options) (*Document, error) {
+	primary := verificationMethod(didKey, didKey[len(DIDKeyPrefix):])
+	doc := &Document{
+		Context:            ContextsForKeyType(keyType, MultikeyType),
+		ID:                 didKey,
+		VerificationMethod: []VerificationMethod{primary},
+	}
+
+	for _, relationship := range VerificationRelationships(keyType) {
+		id := primary.ID
+
+		// Ed25519 keys cannot do key agreement; the spec derives an X25519 key for it
+		if relationship == KeyAgreement && keyType == Ed25519PublicKey {
+			derived, err := derivedKeyAgreementMethod(didKey, keyBytes)
+			if err != nil {
+				return nil, err
+			}
+			doc.VerificationMethod = append(doc.VerificationMethod, derived)
+			id = derived.ID
+
+			if o.alsoKnownAsDerived {
+				doc.AlsoKnownAs = append(doc.AlsoKnownAs, DIDKeyPrefix+derived.PublicKeyMultibase)
+			}
+		}
+
+		doc.addRelationship(relationship, id)
+	}
+
+	if o.relativeMethodIDs {
+		doc.relativizeMethodIDs()
+	}
+
+	return doc, nil
+}
+
+// relativizeMethodIDs rewrites the absolute verification method ids of the
+// document, and the references to them, relative to the document id
+func (d *Document) relativizeMethodIDs() {
+	for i := range d.VerificationMethod {
+		d.VerificationMethod[i].ID = strings.TrimPrefix(d.VerificationMethod[i].ID, d.ID)
+	}
+
+	for _, refs := range [][]string{d.Authentication, d.AssertionMethod, d.CapabilityDelegation, d.CapabilityInvocation, d.KeyAgreement} {
+		for i := range refs {
+			refs[i] = strings.TrimPrefix(refs[i], d.ID)
+		}
+	}
+}
+
+func derivedKeyAgreementMethod(did string, ed25519Key []byte) (VerificationMethod, error) {
+	fingerprint, err := derivedX25519Fingerprint(ed25519Key)
+	if err != nil {
+		return VerificationMethod{}, err
+	}
+
+	return verificationMethod(did, fingerprint), nil
+}
+
+// derivedX25519Fingerprint returns the fingerprint of the X25519 key derived
+// from an Ed25519 key for key agreement
+func derivedX25519Fingerprint(ed25519Key []byte) (string, error) {
+	x25519Key, err := ed25519ToX25519(ed25519Key)
+	if err != nil {
+		return "", err
+	}
+
+	x25519DID, err := Encode(X25519PublicKey, x25519Key)
+	if err != nil {
+		return "", err
+	}
+
+	return x25519DID[len(DIDKeyPrefix):], nil
+}
+
+// MethodRef identifies a verification method of a DID Document by its fragment
+// and key type
+type MethodRef struct {
+	Fragment string // Fingerprint of the method's key, e.g. z6Mk...
+	KeyType  KeyType
+}
+
+// VerificationMethodFragments returns the verification methods ResolveDocument
+// would produce for a DID key, without building the document: the key itself
+// and, for Ed25519, the derived X25519 key agreement key. It is meant for
+// indexers that only need the method ids.
+func VerificationMethodFragments(didKey string, opts ...Option) ([]MethodRef, error) {
+	keyType, keyBytes, err := Decode(didKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := []MethodRef{{Fragment: didKey[len(DIDKeyPrefix):], KeyType: keyType}}
+	if keyType == Ed25519PublicKey {
+		fingerprint, err := derivedX25519Fingerprint(keyBytes)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, MethodRef{Fragment: fingerprint, KeyType: X25519PublicKey})
+	}
+
+	return refs, nil
+}
+
+func verificationMethod(did, fingerprint string) VerificationMethod {
+	return VerificationMethod{
+		ID:                 did + "#" + fingerprint,
+		Type:               MultikeyType,
+		Controller:         did,
+		PublicKeyMultibase: fingerprint,
+	}
+}
+
+// AsVerificationMethodFor returns the Multikey verification method of the DID
+// key as listed by another DID, e.g. when lifting a did:key into a did:web
+// document while migrating. The controller and the id's DID are controllerDID;
+// the fragment and publicKeyMultibase stay the DID key fingerprint:
+//
+//	did:web:example.com#z6Mk...
+//
+// controllerDID must be a DID, not a DID URL.
+func (dk *DIDKey) AsVerificationMethodFor(controllerDID string) (*VerificationMethod, error) {
+	if !isDID(controllerDID) {
+		return nil, ErrInvalidControllerDIDWithContext(controllerDID)
+	}
+
+	vm := verificationMethod(controllerDID, dk.Fingerprint())
+	return &vm, nil
+}
+
+// isDID reports whether s has the syntax of a DID: did:<method>:<id>, with a
+// lowercase alphanumeric method and a non-empty id without path, query or
+// fragment
+func isDID(s string) bool {
+	rest, ok := strings.CutPrefix(s, "did:")
+	if !ok {
+		return false
+	}
+
+	method, id, ok := strings.Cut(rest, ":")
+	if !ok || method == "" || id == "" || strings.ContainsAny(id, "/?#") {
+		return false
+	}
+
+	for _, r := range method {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MethodsByRelationship maps each verification relationship of the document to
+// the verification methods it references, in order. References may be absolute
+// (did:key:z6Mk...#z6Mk...) or relative to the document id (#z6Mk...); those
+// not matching a verification method of the document are skipped. The methods
+// point into d.VerificationMethod.
+func (d *Document) MethodsByRelationship() map[string][]*VerificationMethod {
+	methods := make(map[string]*VerificationMethod, len(d.VerificationMethod))
+	for i := range d.VerificationMethod {
+		methods[d.absoluteID(d.VerificationMethod[i].ID)] = &d.VerificationMethod[i]
+	}
+
+	byRelationship := make(map[string][]*VerificationMethod)
+	for relationship, refs := range map[string][]string{
+		Authentication:       d.Authentication,
+		AssertionMethod:      d.AssertionMethod,
+		CapabilityDelegation: d.CapabilityDelegation,
+		CapabilityInvocation: d.CapabilityInvocation,
+		KeyAgreement:         d.KeyAgreement,
+	} {
+		for _, ref := range refs {
+			if vm, ok := methods[d.absoluteID(ref)]; ok {
+				byRelationship[relationship] = append(byRelationship[relationship], vm)
+			}
+		}
+	}
+
+	return byRelationship
+}
+
+// absoluteID resolves an id relative to the document (#fragment) against the document id
+func (d *Document) absoluteID(id string) string {
+	if strings.HasPrefix(id, "#") {
+		return d.ID + id
+	}
+	return id
+}
+
+func (d *Document) addRelationship(relationship, id string) {
+	switch relationship {
+	case Authentication:
+		d.Authentication = append(d.Authentication, id)
+	case AssertionMethod:
+		d.AssertionMethod = append(d.AssertionMethod, id)
+	case CapabilityDelegation:
+		d.CapabilityDelegation = append(d.CapabilityDelegation, id)
+	case CapabilityInvocation:
+		d.CapabilityInvocation = append(d.CapabilityInvocation, id)
+	case KeyAgreement:
+		d.KeyAgreement = append(d.KeyAgreement, id)
+	}
+}
+
+// ParseDocument parses a JSON DID Document, such as one produced by ResolveDocument
+func ParseDocument(jsonBytes []byte) (*Document, error) {
+	var doc Document
+	if err := json.Unmarshal(jsonBytes, &doc); err != nil {
+		return nil, ErrInvalidDocumentWithContext(err)
+	}
+
+	return &doc, nil
+}
+
+// PrimaryKey returns the key of the first verification method of the document.
+// For documents resolved from a DID key this is the DID key itself.
+func (d *Document) PrimaryKey(opts ...Option) (*DIDKey, error) {
+	if len(d.VerificationMethod) == 0 {
+		return nil, ErrNoVerificationMethods
+	}
+
+	return d.VerificationMethod[0].PublicKey(opts...)
+}
+
+// PublicKey decodes the public key of a verification method. Multikey,
+// JsonWebKey2020, Ed25519VerificationKey2018 and X25519KeyAgreementKey2019
+// verification methods are supported.
+func (vm *VerificationMethod) PublicKey(opts ...Option) (*DIDKey, error) {
+	switch vm.Type {
+	case MultikeyType:
+		return vm.multikeyPublicKey(opts)
+	case JSONWebKey2020Type:
+		if vm.PublicKeyJwk == nil {
+			return nil, ErrInvalidJWKWithContext("missing publicKeyJwk")
+		}
+		return vm.PublicKeyJwk.publicKey(opts...)
+	case Ed25519VerificationKey2018Type:
+		return vm.base58PublicKey(Ed25519PublicKey, opts)
+	case X25519KeyAgreementKey2019Type:
+		return vm.base58PublicKey(X25519PublicKey, opts)
+	default:
+		return nil, ErrUnsupportedVerificationMethodTypeWithContext(vm.Type)
+	}
+}
+
+func (vm *VerificationMethod) multikeyPublicKey(opts []Option) (*DIDKey, error) {
+	if vm.PublicKeyMultibase == "" {
+		return nil, ErrEmptyMultibaseString
+	}
+
+	multicodecBytes, err := decodeMultibase(vm.PublicKeyMultibase)
+	if err != nil {
+		return nil, err
+	}
+
+	keyType, keyBytes, err := decodeMulticodec(multicodecBytes, applyOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DIDKey{keyType: keyType, keyBytes: bytes.Clone(keyBytes)}, nil
+}
+
+func (vm *VerificationMethod) base58PublicKey(keyType KeyType, opts []Option) (*DIDKey, error) {
+	keyBytes, err := decodeBase58BTC(vm.PublicKeyBase58)
+	if err != nil {
+		return nil, ErrInvalidDocumentWithContext(err)
+	}
+
+	return FromBytes(keyType, keyBytes, opts...)
+}
+
+// SameDocument reports whether two DID keys resolve to the same DID Document,
+// for use as a document cache key. A did:key document is fully determined by
+// its key, so this holds exactly when both DID keys encode the same key type
+// and key bytes.
+//
+// In particular, an Ed25519 DID key and the X25519 DID key derived from it are
+// not the same document, even though the Ed25519 document lists the X25519 key
+// under keyAgreement (see IsDerivedX25519): the X25519 document has its own id
+// and only a keyAgreement relationship.
+func SameDocument(a, b string, opts ...Option) (bool, error) {
+	aType, aBytes, err := Decode(a, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	bType, bBytes, err := Decode(b, opts...)
+	if err != nil {
+		return false, err
+	}
+
+	return aType == bType && bytes.Equal(aBytes, bBytes), nil
+}
+
+// Equal reports whether two documents are semantically equal: the same id and
+// @context (in order, as JSON-LD context order is significant), the same
+// verification methods in any order, and the same alsoKnownAs DIDs,
+// verification relationships and services compared as sets.
+func (d *Document) Equal(other *Document) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+
+	return d.ID == other.ID &&
+		slices.Equal(d.Context, other.Context) &&
+		sameSet(d.AlsoKnownAs, other.AlsoKnownAs) &&
+		sameSet(comparableMethods(d.VerificationMethod), comparableMethods(other.VerificationMethod)) &&
+		sameSet(d.Authentication, other.Authentication) &&
+		sameSet(d.AssertionMethod, other.AssertionMethod) &&
+		sameSet(d.CapabilityDelegation, other.CapabilityDelegation) &&
+		sameSet(d.CapabilityInvocation, other.CapabilityInvocation) &&
+		sameSet(d.KeyAgreement, other.KeyAgreement) &&
+		sameSet(d.Service, other.Service)
+}
+
+// Clone returns a deep copy of the document that shares no memory with the original
+func (d *Document) Clone() *Document {
+	if d == nil {
+		return nil
+	}
+
+	clone := &Document{
+		Context:              slices.Clone(d.Context),
+		ID:                   d.ID,
+		AlsoKnownAs:          slices.Clone(d.AlsoKnownAs),
+		VerificationMethod:   slices.Clone(d.VerificationMethod),
+		Authentication:       slices.Clone(d.Authentication),
+		AssertionMethod:      slices.Clone(d.AssertionMethod),
+		CapabilityDelegation: slices.Clone(d.CapabilityDelegation),
+		CapabilityInvocation: slices.Clone(d.CapabilityInvocation),
+		KeyAgreement:         slices.Clone(d.KeyAgreement),
+		Service:              slices.Clone(d.Service),
+	}
+	for i, vm := range clone.VerificationMethod {
+		if vm.PublicKeyJwk != nil {
+			jwk := *vm.PublicKeyJwk
+			clone.VerificationMethod[i].PublicKeyJwk = &jwk
+		}
+	}
+	return clone
+}
+
+// comparableMethod is a verification method with its JWK held by value, so
+// that equal verification methods compare equal with ==
+type comparableMethod struct {
+	vm  VerificationMethod
+	jwk JWK
+}
+
+func comparableMethods(vms []VerificationMethod) []comparableMethod {
+	out := make([]comparableMethod, len(vms))
+	for i, vm := range vms {
+		if vm.PublicKeyJwk != nil {
+			out[i].jwk = *vm.PublicKeyJwk
+			vm.PublicKeyJwk = nil
+		}
+		out[i].vm = vm
+	}
+	return out
+}
+
+// sameSet reports whether a and b contain the same elements, ignoring order and duplicates
+func sameSet[T comparable](a, b []T) bool {
+	setA := make(map[T]struct{}, len(a))
+	for _, v := range a {
+		setA[v] = struct{}{}
+	}
+
+	setB := make(map[T]struct{}, len(b))
+	for _, v := range b {
+		if _, ok := setA[v]; !ok {
+			return false
+		}
+		setB[v] = struct{}{}
+	}
+
+	return len(setA) == len(setB)
+}
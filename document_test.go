@@ -0,0 +1,709 @@
+package didkey
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// updateGolden regenerates the golden files of TestDocumentGolden:
+//
+//	go test -run TestDocumentGolden -update
+var updateGolden = flag.Bool("update", false, "regenerate the golden DID Documents in testdata")
+
+func TestVerificationRelationships(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyType  KeyType
+		expected []string
+	}{
+		{
+			name:     "Ed25519",
+			keyType:  Ed25519PublicKey,
+			expected: []string{Authentication, AssertionMethod, CapabilityDelegation, CapabilityInvocation, KeyAgreement},
+		},
+		{
+			name:     "X25519",
+			keyType:  X25519PublicKey,
+			expected: []string{KeyAgreement},
+		},
+		{
+			name:     "Secp256k1",
+			keyType:  Secp256k1PublicKey,
+			expected: []string{Authentication, AssertionMethod, CapabilityDelegation, CapabilityInvocation},
+		},
+		{
+			name:     "Unsupported",
+			keyType:  KeyType(0x1234),
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relationships := VerificationRelationships(tt.keyType)
+			if !slices.Equal(relationships, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, relationships)
+			}
+		})
+	}
+}
+
+func TestResolveDocument(t *testing.T) {
+	t.Run("Ed25519 from spec", func(t *testing.T) {
+		did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+		primaryID := did + "#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+		derivedID := did + "#z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"
+
+		doc, err := ResolveDocument(did)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if doc.ID != did {
+			t.Errorf("Expected id %s, got %s", did, doc.ID)
+		}
+
+		if len(doc.VerificationMethod) != 2 {
+			t.Fatalf("Expected 2 verification methods, got %d", len(doc.VerificationMethod))
+		}
+
+		if doc.VerificationMethod[0].ID != primaryID {
+			t.Errorf("Expected primary method %s, got %s", primaryID, doc.VerificationMethod[0].ID)
+		}
+
+		if doc.VerificationMethod[1].ID != derivedID {
+			t.Errorf("Expected derived method %s, got %s", derivedID, doc.VerificationMethod[1].ID)
+		}
+
+		for _, vm := range doc.VerificationMethod {
+			if vm.Type != MultikeyType || vm.Controller != did {
+				t.Errorf("Unexpected verification method %+v", vm)
+			}
+		}
+
+		for name, refs := range map[string][]string{
+			Authentication:       doc.Authentication,
+			AssertionMethod:      doc.AssertionMethod,
+			CapabilityDelegation: doc.CapabilityDelegation,
+			CapabilityInvocation: doc.CapabilityInvocation,
+		} {
+			if !slices.Equal(refs, []string{primaryID}) {
+				t.Errorf("Expected %s to be [%s], got %v", name, primaryID, refs)
+			}
+		}
+
+		if !slices.Equal(doc.KeyAgreement, []string{derivedID}) {
+			t.Errorf("Expected keyAgreement to be [%s], got %v", derivedID, doc.KeyAgreement)
+		}
+	})
+
+	t.Run("X25519", func(t *testing.T) {
+		did := "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"
+
+		doc, err := ResolveDocument(did)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(doc.VerificationMethod) != 1 {
+			t.Fatalf("Expected 1 verification method, got %d", len(doc.VerificationMethod))
+		}
+
+		if len(doc.Authentication) != 0 || len(doc.AssertionMethod) != 0 {
+			t.Errorf("Expected no signature relationships for X25519")
+		}
+
+		if !slices.Equal(doc.KeyAgreement, []string{doc.VerificationMethod[0].ID}) {
+			t.Errorf("Expected keyAgreement to reference the key, got %v", doc.KeyAgreement)
+		}
+	})
+
+	t.Run("invalid DID key", func(t *testing.T) {
+		if _, err := ResolveDocument("did:web:example.com"); err == nil {
+			t.Errorf("Expected error but got none")
+		}
+	})
+}
+
+func TestResolveDocumentDIDURL(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	t.Run("fragment is ignored", func(t *testing.T) {
+		doc, err := ResolveDocument(did + "#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if doc.ID != did {
+			t.Errorf("Expected id %s, got %s", did, doc.ID)
+		}
+	})
+
+	for _, query := range []string{
+		"versionId=1",
+		"versionTime=2021-05-10T17:00:00Z",
+		"versionId=1&versionTime=2021-05-10T17:00:00Z",
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, err := ResolveDocument(did + "?" + query)
+
+			var resolutionErr *ResolutionError
+			if !errors.As(err, &resolutionErr) {
+				t.Fatalf("Expected ResolutionError, got %v", err)
+			}
+
+			if resolutionErr.Code != ResolutionNotFound {
+				t.Errorf("Expected code %s, got %s", ResolutionNotFound, resolutionErr.Code)
+			}
+
+			if !errors.Is(err, ErrVersioningUnsupported) {
+				t.Errorf("Expected ErrVersioningUnsupported, got %v", err)
+			}
+		})
+	}
+
+	t.Run("version parameters still parse", func(t *testing.T) {
+		u, err := ParseDIDURL(did + "?versionId=1&versionTime=2021-05-10T17:00:00Z")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if u.Query != "versionId=1&versionTime=2021-05-10T17:00:00Z" {
+			t.Errorf("Unexpected query %q", u.Query)
+		}
+	})
+
+	t.Run("invalid DID", func(t *testing.T) {
+		_, err := ResolveDocument("did:key:zinvalid")
+
+		var resolutionErr *ResolutionError
+		if !errors.As(err, &resolutionErr) || resolutionErr.Code != ResolutionInvalidDID {
+			t.Errorf("Expected invalidDid ResolutionError, got %v", err)
+		}
+	})
+}
+
+func TestDocumentRoundTrip(t *testing.T) {
+	for name, tv := range testVectors {
+		t.Run(name, func(t *testing.T) {
+			original, err := Parse(tv.didKey)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			doc, err := ResolveDocument(original.String())
+			if err != nil {
+				t.Fatalf("ResolveDocument failed: %v", err)
+			}
+
+			jsonBytes, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			parsed, err := ParseDocument(jsonBytes)
+			if err != nil {
+				t.Fatalf("ParseDocument failed: %v", err)
+			}
+
+			primary, err := parsed.PrimaryKey()
+			if err != nil {
+				t.Fatalf("PrimaryKey failed: %v", err)
+			}
+
+			if primary.String() != original.String() {
+				t.Errorf("Expected %s, got %s", original.String(), primary.String())
+			}
+		})
+	}
+
+	t.Run("derived key agreement key", func(t *testing.T) {
+		doc, err := ResolveDocument("did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK")
+		if err != nil {
+			t.Fatalf("ResolveDocument failed: %v", err)
+		}
+
+		derived, err := doc.VerificationMethod[1].PublicKey()
+		if err != nil {
+			t.Fatalf("PublicKey failed: %v", err)
+		}
+
+		if derived.String() != "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p" {
+			t.Errorf("Unexpected derived key %s", derived.String())
+		}
+	})
+}
+
+func TestParseDocumentErrors(t *testing.T) {
+	if _, err := ParseDocument([]byte("{")); !errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("Expected ErrInvalidDocument, got %v", err)
+	}
+
+	doc, err := ParseDocument([]byte(`{"id":"did:key:z6Mk","verificationMethod":[]}`))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	if _, err := doc.PrimaryKey(); !errors.Is(err, ErrNoVerificationMethods) {
+		t.Errorf("Expected ErrNoVerificationMethods, got %v", err)
+	}
+
+	doc, err = ParseDocument([]byte(`{
+		"id": "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+		"verificationMethod": [{
+			"id": "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			"type": "RsaVerificationKey2018",
+			"controller": "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+		}]
+	}`))
+	if err != nil {
+		t.Fatalf("ParseDocument failed: %v", err)
+	}
+	if _, err := doc.PrimaryKey(); !errors.Is(err, ErrUnsupportedVerificationMethodType) {
+		t.Errorf("Expected ErrUnsupportedVerificationMethodType, got %v", err)
+	}
+}
+
+func TestDocumentEqual(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	resolve := func() *Document {
+		doc, err := ResolveDocument(did)
+		if err != nil {
+			t.Fatalf("ResolveDocument failed: %v", err)
+		}
+		return doc
+	}
+
+	t.Run("identical", func(t *testing.T) {
+		if !resolve().Equal(resolve()) {
+			t.Errorf("Expected documents to be equal")
+		}
+	})
+
+	t.Run("reordered verification methods", func(t *testing.T) {
+		a, b := resolve(), resolve()
+		slices.Reverse(b.VerificationMethod)
+		b.Authentication = append(b.Authentication, b.Authentication...)
+
+		if !a.Equal(b) || !b.Equal(a) {
+			t.Errorf("Expected reordered documents to be equal")
+		}
+	})
+
+	t.Run("different relationships", func(t *testing.T) {
+		a, b := resolve(), resolve()
+		b.KeyAgreement = b.Authentication
+
+		if a.Equal(b) {
+			t.Errorf("Expected documents with different keyAgreement to differ")
+		}
+	})
+
+	t.Run("different verification method", func(t *testing.T) {
+		a, b := resolve(), resolve()
+		b.VerificationMethod = b.VerificationMethod[:1]
+
+		if a.Equal(b) {
+			t.Errorf("Expected documents with different verification methods to differ")
+		}
+	})
+
+	t.Run("context order matters", func(t *testing.T) {
+		a, b := resolve(), resolve()
+		slices.Reverse(b.Context)
+
+		if a.Equal(b) {
+			t.Errorf("Expected documents with reordered contexts to differ")
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var doc *Document
+		if !doc.Equal(nil) || resolve().Equal(nil) {
+			t.Errorf("Unexpected nil comparison result")
+		}
+	})
+}
+
+func TestContextsForKeyType(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyType  KeyType
+		vmType   string
+		expected []string
+	}{
+		{
+			name:     "Ed25519 Multikey",
+			keyType:  Ed25519PublicKey,
+			vmType:   MultikeyType,
+			expected: []string{"https://www.w3.org/ns/did/v1", "https://w3id.org/security/multikey/v1"},
+		},
+		{
+			name:    "Ed25519 2018",
+			keyType: Ed25519PublicKey,
+			vmType:  Ed25519VerificationKey2018Type,
+			expected: []string{
+				"https://www.w3.org/ns/did/v1",
+				"https://w3id.org/security/suites/ed25519-2018/v1",
+				"https://w3id.org/security/suites/x25519-2019/v1",
+			},
+		},
+		{
+			name:     "P-256 JsonWebKey2020",
+			keyType:  P256PublicKey,
+			vmType:   JSONWebKey2020Type,
+			expected: []string{"https://www.w3.org/ns/did/v1", "https://w3id.org/security/suites/jws-2020/v1"},
+		},
+		{
+			name:     "X25519 2019",
+			keyType:  X25519PublicKey,
+			vmType:   X25519KeyAgreementKey2019Type,
+			expected: []string{"https://www.w3.org/ns/did/v1", "https://w3id.org/security/suites/x25519-2019/v1"},
+		},
+		{
+			name:    "secp256k1 cannot be Ed25519VerificationKey2018",
+			keyType: Secp256k1PublicKey,
+			vmType:  Ed25519VerificationKey2018Type,
+		},
+		{
+			name:    "BLS has no JWK",
+			keyType: Bls12381G2PublicKey,
+			vmType:  JSONWebKey2020Type,
+		},
+		{
+			name:    "unknown verification method type",
+			keyType: Ed25519PublicKey,
+			vmType:  "RsaVerificationKey2018",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contexts := ContextsForKeyType(tt.keyType, tt.vmType)
+			if !slices.Equal(contexts, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, contexts)
+			}
+		})
+	}
+}
+
+func TestSameDocument(t *testing.T) {
+	// Derivation pair from the did:key specification
+	ed25519DID := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	x25519DID := "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"
+
+	// An X25519 DID key with the same raw bytes as the Ed25519 key
+	sameBytesDID, err := Encode(X25519PublicKey, mustDecodeHex(testVectors["Ed25519-from-spec"].keyHex))
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		a, b     string
+		expected bool
+		err      error
+	}{
+		{name: "identical keys", a: ed25519DID, b: ed25519DID, expected: true},
+		{name: "different keys", a: ed25519DID, b: testVectors["Ed25519-test-1"].didKey, expected: false},
+		{name: "Ed25519 and derived X25519", a: ed25519DID, b: x25519DID, expected: false},
+		{name: "same bytes, different key type", a: ed25519DID, b: sameBytesDID, expected: false},
+		{name: "invalid first DID key", a: "did:key:invalid", b: ed25519DID, err: ErrInvalidFingerprintLength},
+		{name: "invalid second DID key", a: ed25519DID, b: "did:web:example.com", err: ErrInvalidDIDKeyPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			same, err := SameDocument(tt.a, tt.b)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("Expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SameDocument failed: %v", err)
+			}
+			if same != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, same)
+			}
+
+			if tt.expected {
+				docA, _ := ResolveDocument(tt.a)
+				docB, _ := ResolveDocument(tt.b)
+				if !docA.Equal(docB) {
+					t.Errorf("Expected equal documents")
+				}
+			}
+		})
+	}
+}
+
+func TestAsVerificationMethodFor(t *testing.T) {
+	tv := testVectors["Ed25519-from-spec"]
+	dk, err := Parse(tv.didKey)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	fingerprint := tv.didKey[len(DIDKeyPrefix):]
+
+	t.Run("did:web controller", func(t *testing.T) {
+		vm, err := dk.AsVerificationMethodFor("did:web:example.com")
+		if err != nil {
+			t.Fatalf("AsVerificationMethodFor failed: %v", err)
+		}
+
+		expected := VerificationMethod{
+			ID:                 "did:web:example.com#" + fingerprint,
+			Type:               MultikeyType,
+			Controller:         "did:web:example.com",
+			PublicKeyMultibase: fingerprint,
+		}
+		if *vm != expected {
+			t.Errorf("Expected %+v, got %+v", expected, *vm)
+		}
+
+		// The lifted method still yields the original key
+		publicKey, err := vm.PublicKey()
+		if err != nil {
+			t.Fatalf("PublicKey failed: %v", err)
+		}
+		if publicKey.String() != tv.didKey {
+			t.Errorf("Expected %s, got %s", tv.didKey, publicKey)
+		}
+	})
+
+	for _, controller := range []string{"", "example.com", "did:web", "did::example.com", "did:WEB:example.com", "did:web:", "did:web:example.com#key-1", "did:web:example.com/path"} {
+		t.Run("invalid "+controller, func(t *testing.T) {
+			if _, err := dk.AsVerificationMethodFor(controller); !errors.Is(err, ErrInvalidControllerDID) {
+				t.Errorf("Expected ErrInvalidControllerDID, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMethodsByRelationship(t *testing.T) {
+	didKey := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	doc, err := ResolveDocument(didKey)
+	if err != nil {
+		t.Fatalf("ResolveDocument failed: %v", err)
+	}
+
+	methods := doc.MethodsByRelationship()
+
+	ed25519ID := didKey + "#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	x25519ID := didKey + "#z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"
+	for _, relationship := range []string{Authentication, AssertionMethod, CapabilityDelegation, CapabilityInvocation} {
+		if vms := methods[relationship]; len(vms) != 1 || vms[0].ID != ed25519ID {
+			t.Errorf("Expected %s to resolve to %s, got %v", relationship, ed25519ID, vms)
+		}
+	}
+
+	keyAgreement := methods[KeyAgreement]
+	if len(keyAgreement) != 1 || keyAgreement[0].ID != x25519ID {
+		t.Fatalf("Expected keyAgreement to resolve to %s, got %v", x25519ID, keyAgreement)
+	}
+	if keyAgreement[0] != &doc.VerificationMethod[1] {
+		t.Errorf("Expected the method to point into the document")
+	}
+
+	t.Run("relative and dangling references", func(t *testing.T) {
+		doc.Authentication = []string{"#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", didKey + "#missing"}
+		doc.KeyAgreement = nil
+
+		methods := doc.MethodsByRelationship()
+		if vms := methods[Authentication]; len(vms) != 1 || vms[0].ID != ed25519ID {
+			t.Errorf("Expected authentication to resolve to %s, got %v", ed25519ID, vms)
+		}
+		if _, ok := methods[KeyAgreement]; ok {
+			t.Errorf("Expected no keyAgreement entry, got %v", methods[KeyAgreement])
+		}
+	})
+}
+
+func TestVerificationMethodFragments(t *testing.T) {
+	tests := []struct {
+		name     string
+		didKey   string
+		expected []MethodRef
+	}{
+		{
+			name:   "Ed25519",
+			didKey: "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			expected: []MethodRef{
+				{Fragment: "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", KeyType: Ed25519PublicKey},
+				{Fragment: "z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p", KeyType: X25519PublicKey},
+			},
+		},
+		{
+			name:   "X25519",
+			didKey: "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p",
+			expected: []MethodRef{
+				{Fragment: "z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p", KeyType: X25519PublicKey},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, err := VerificationMethodFragments(tt.didKey)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(refs, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, refs)
+			}
+
+			// The fragments are those of the resolved document
+			doc, err := ResolveDocument(tt.didKey)
+			if err != nil {
+				t.Fatalf("ResolveDocument failed: %v", err)
+			}
+			for i, vm := range doc.VerificationMethod {
+				if vm.ID != tt.didKey+"#"+refs[i].Fragment {
+					t.Errorf("Expected method %s, got fragment %s", vm.ID, refs[i].Fragment)
+				}
+			}
+		})
+	}
+
+	if _, err := VerificationMethodFragments("did:web:example.com"); !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+		t.Errorf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+	}
+}
+
+func TestWithAlsoKnownAsDerived(t *testing.T) {
+	t.Run("Ed25519", func(t *testing.T) {
+		doc, err := ResolveDocument("did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", WithAlsoKnownAsDerived())
+		if err != nil {
+			t.Fatalf("ResolveDocument failed: %v", err)
+		}
+
+		jsonBytes, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+
+		const expected = `{"@context":["https://www.w3.org/ns/did/v1","https://w3id.org/security/multikey/v1"],` +
+			`"id":"did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",` +
+			`"alsoKnownAs":["did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"],` +
+			`"verificationMethod":[` +
+			`{"id":"did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK","type":"Multikey","controller":"did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK","publicKeyMultibase":"z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"},` +
+			`{"id":"did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p","type":"Multikey","controller":"did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK","publicKeyMultibase":"z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"}],` +
+			`"authentication":["did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"],` +
+			`"assertionMethod":["did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"],` +
+			`"capabilityDelegation":["did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"],` +
+			`"capabilityInvocation":["did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"],` +
+			`"keyAgreement":["did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"]}`
+		if string(jsonBytes) != expected {
+			t.Errorf("Expected %s, got %s", expected, jsonBytes)
+		}
+	})
+
+	t.Run("not set by default", func(t *testing.T) {
+		doc, err := ResolveDocument("did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK")
+		if err != nil {
+			t.Fatalf("ResolveDocument failed: %v", err)
+		}
+		if doc.AlsoKnownAs != nil {
+			t.Errorf("Expected no alsoKnownAs, got %v", doc.AlsoKnownAs)
+		}
+	})
+
+	t.Run("X25519", func(t *testing.T) {
+		doc, err := ResolveDocument("did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p", WithAlsoKnownAsDerived())
+		if err != nil {
+			t.Fatalf("ResolveDocument failed: %v", err)
+		}
+		if doc.AlsoKnownAs != nil {
+			t.Errorf("Expected no alsoKnownAs, got %v", doc.AlsoKnownAs)
+		}
+	})
+}
+
+func TestDocumentGolden(t *testing.T) {
+	tests := []struct {
+		golden string
+		didKey string
+		opts   []Option
+	}{
+		{"ed25519_document.json", "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", nil},
+		{"ed25519_document_relative.json", "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", []Option{WithRelativeMethodIDs()}},
+		{"x25519_document.json", "did:key:z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F", nil},
+		{"secp256k1_document.json", "did:key:zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme", nil},
+		{"p256_document.json", "did:key:zDnaerDaTF5BXEavCrfRZEk316dpbLsfPDZ3WJ5hRTPFU2169", nil},
+		{"p384_document.json", "did:key:z82Lm1MpAkeJcix9K8TMiLd5NMAhnwkjjCBeWHXyu3U4oT2MVJJKXkcVBgjGhnLBn2Kaau9", nil},
+		{"bls12381g1_document.json", "did:key:z3tEFALUKUzzCAvytMHX8X4SnsNsq6T5tC5Zb18oQEt1FqNcJXqJ3AA9umgzA9yoqPBeWA", nil},
+		{"bls12381g2_document.json", "did:key:zUC7EK3ZakmukHhuncwkbySmomv3FmrkmS36E4Ks5rsb6VQSRpoCrx6Hb8e2Nk6UvJFSdyw9NK1scFXJp21gNNYFjVWNgaqyGnkyhtagagCpQb5B7tagJu3HDbjQ8h5ypoHjwBb", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			doc, err := ResolveDocument(tt.didKey, tt.opts...)
+			if err != nil {
+				t.Fatalf("ResolveDocument failed: %v", err)
+			}
+
+			got, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", tt.golden)
+			if *updateGolden {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatalf("Failed to update golden file: %v", err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read golden file (regenerate with -update): %v", err)
+			}
+
+			if !bytes.Equal(got, expected) {
+				t.Errorf("Document does not match %s (regenerate with -update if the change is intended):\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestEncodeWithDocument(t *testing.T) {
+	for name, tv := range testVectors {
+		t.Run(name, func(t *testing.T) {
+			didKey, doc, err := EncodeWithDocument(tv.keyType, mustDecodeHex(tv.keyHex))
+			if err != nil {
+				t.Fatalf("EncodeWithDocument failed: %v", err)
+			}
+
+			if didKey != tv.didKey {
+				t.Errorf("Expected %s, got %s", tv.didKey, didKey)
+			}
+			if doc.ID != didKey {
+				t.Errorf("Expected document id %s, got %s", didKey, doc.ID)
+			}
+
+			resolved, err := ResolveDocument(didKey)
+			if err != nil {
+				t.Fatalf("ResolveDocument failed: %v", err)
+			}
+			if !doc.Equal(resolved) {
+				t.Errorf("Expected the resolved document %+v, got %+v", resolved, doc)
+			}
+		})
+	}
+
+	t.Run("invalid key", func(t *testing.T) {
+		if _, _, err := EncodeWithDocument(Ed25519PublicKey, make([]byte, 31)); !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected ErrInvalidKeySize, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,288 @@
+package didkey
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ecCurve holds the short Weierstrass parameters y² = x³ + ax + b (mod p)
+// of an elliptic curve used by did:key EC key types
+type ecCurve struct {
+	keyType KeyType
+	name    string
+	p       *big.Int
+	a       *big.Int
+	b       *big.Int
+	size    int // Coordinate size in bytes
+}
+
+var (
+	secp256k1Curve = &ecCurve{
+		keyType: Secp256k1PublicKey,
+		name:    "secp256k1",
+		p:       hexInt("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f"),
+		a:       big.NewInt(0),
+		b:       big.NewInt(7),
+		size:    32,
+	}
+	p256Curve = &ecCurve{
+		keyType: P256PublicKey,
+		name:    "P-256",
+		p:       hexInt("ffffffff00000001000000000000000000000000ffffffffffffffffffffffff"),
+		a:       big.NewInt(-3),
+		b:       hexInt("5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604b"),
+		size:    32,
+	}
+	p384Curve = &ecCurve{
+		keyType: P384PublicKey,
+		name:    "P-384",
+		p:       hexInt("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffff0000000000000000ffffffff"),
+		a:       big.NewInt(-3),
+		b:       hexInt("b3312fa7e23ee7e4988e056be3f82d19181d9c6efe8141120314088f5013875ac656398d8a2ed19d2a85c8edd3ec2aef"),
+		size:    48,
+	}
+)
+
+func hexInt(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("didkey: invalid curve constant " + s)
+	}
+	return n
+}
+
+// EncodeECCoordinates converts the big-endian X and Y coordinates of an EC public key
+// to a DID key string. The point must lie on the curve of the key type; it is
+// compressed before encoding.
+func EncodeECCoordinates(keyType KeyType, x, y []byte) (string, error) {
+	curve, ok := curveFor(keyType)
+	if !ok {
+		return "", ErrUnsupportedKeyTypeWithContext(keyType)
+	}
+
+	if len(x) != curve.size || len(y) != curve.size {
+		return "", ErrInvalidCoordinateSizeWithContext(keyType, curve.size, len(x), len(y))
+	}
+
+	xInt := new(big.Int).SetBytes(x)
+	yInt := new(big.Int).SetBytes(y)
+	if !curve.isOnCurve(xInt, yInt) {
+		return "", ErrInvalidPointWithContext(keyType)
+	}
+
+	// The point has already been validated
+	return Encode(keyType, curve.compress(xInt, yInt), WithCurveValidation(false))
+}
+
+// curveFor returns the curve parameters for an EC key type
+func curveFor(keyType KeyType) (*ecCurve, bool) {
+	switch keyType {
+	case Secp256k1PublicKey:
+		return secp256k1Curve, true
+	case P256PublicKey:
+		return p256Curve, true
+	case P384PublicKey:
+		return p384Curve, true
+	default:
+		return nil, false
+	}
+}
+
+// ECPointInfo reports the point encoding of an EC key. DID keys always store
+// EC keys in SEC 1 compressed form, so compressed is always true; yOdd is the
+// parity of the y coordinate given by the 0x02 (even) or 0x03 (odd) prefix.
+// It returns ErrUnsupportedKeyType for non-EC keys.
+func (dk *DIDKey) ECPointInfo() (compressed bool, yOdd bool, err error) {
+	if _, ok := curveFor(dk.keyType); !ok {
+		return false, false, ErrUnsupportedKeyTypeWithContext(dk.keyType)
+	}
+
+	return true, dk.keyBytes[0] == 0x03, nil
+}
+
+// curveByName returns the EC key type and curve parameters for a JWK curve name
+func curveByName(name string) (KeyType, *ecCurve, bool) {
+	for _, keyType := range []KeyType{Secp256k1PublicKey, P256PublicKey, P384PublicKey} {
+		if curve, _ := curveFor(keyType); curve.name == name {
+			return keyType, curve, true
+		}
+	}
+	return 0, nil, false
+}
+
+// rhs computes x³ + ax + b (mod p)
+func (c *ecCurve) rhs(x *big.Int) *big.Int {
+	r := new(big.Int).Mul(x, x)
+	r.Mul(r, x)
+	r.Add(r, new(big.Int).Mul(c.a, x))
+	r.Add(r, c.b)
+	return r.Mod(r, c.p)
+}
+
+// isOnCurve, compress and decompressPoint use the CurveOps registered for the
+// key type of the curve (see RegisterCurveOps)
+func (c *ecCurve) isOnCurve(x, y *big.Int) bool {
+	return c.ops().OnCurve(x, y)
+}
+
+func (c *ecCurve) compress(x, y *big.Int) []byte {
+	return c.ops().Compress(x, y)
+}
+
+func (c *ecCurve) decompressPoint(data []byte) (x, y *big.Int, err error) {
+	return c.ops().Decompress(data)
+}
+
+// OnCurve reports whether (x, y) is a point on the curve with both coordinates in [0, p)
+func (c *ecCurve) OnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || x.Cmp(c.p) >= 0 || y.Sign() < 0 || y.Cmp(c.p) >= 0 {
+		return false
+	}
+
+	lhs := new(big.Int).Mul(y, y)
+	lhs.Mod(lhs, c.p)
+	return lhs.Cmp(c.rhs(x)) == 0
+}
+
+// Compress returns the SEC 1 compressed form of (x, y): a 0x02/0x03 parity byte followed by x
+func (c *ecCurve) Compress(x, y *big.Int) []byte {
+	out := make([]byte, 1+c.size)
+	out[0] = 0x02 | byte(y.Bit(0))
+	x.FillBytes(out[1:])
+	return out
+}
+
+// decompress recovers the point encoded by SEC 1 compressed key bytes. It
+// reports false if the bytes do not encode a point on the curve.
+func (c *ecCurve) decompress(data []byte) (x, y *big.Int, ok bool) {
+	x, y, err := c.decompressPoint(data)
+	return x, y, err == nil
+}
+
+var (
+	errNotOnCurve     = errors.New("not on curve")
+	errParityMismatch = errors.New("no y with the parity of the prefix")
+)
+
+// Decompress is decompress reporting why the bytes are not a point:
+// errParityMismatch when x is on the curve but its only square root y = 0 is
+// even while the prefix claims an odd y, errNotOnCurve otherwise. The curves
+// used by DID keys have prime order and so no point with y = 0, but the
+// parity is checked rather than assumed.
+func (c *ecCurve) Decompress(data []byte) (x, y *big.Int, err error) {
+	if len(data) != 1+c.size || (data[0] != 0x02 && data[0] != 0x03) {
+		return nil, nil, errNotOnCurve
+	}
+
+	x = new(big.Int).SetBytes(data[1:])
+	if x.Cmp(c.p) >= 0 {
+		return nil, nil, errNotOnCurve
+	}
+
+	y = new(big.Int).ModSqrt(c.rhs(x), c.p)
+	if y == nil {
+		return nil, nil, errNotOnCurve
+	}
+
+	if y.Bit(0) != uint(data[0]&1) {
+		if y.Sign() == 0 {
+			return nil, nil, errParityMismatch
+		}
+		y.Sub(c.p, y)
+	}
+
+	return x, y, nil
+}
+
+// compressUncompressed validates a SEC 1 uncompressed key (0x04 || X || Y)
+// and returns its compressed form
+func (c *ecCurve) compressUncompressed(keyType KeyType, keyBytes []byte) ([]byte, error) {
+	if len(keyBytes) != 1+2*c.size || keyBytes[0] != 0x04 {
+		return nil, ErrInvalidUncompressedPointWithContext(keyType)
+	}
+
+	x := new(big.Int).SetBytes(keyBytes[1 : 1+c.size])
+	y := new(big.Int).SetBytes(keyBytes[1+c.size:])
+	if !c.isOnCurve(x, y) {
+		return nil, ErrInvalidUncompressedPointWithContext(keyType)
+	}
+
+	return c.compress(x, y), nil
+}
+
+// isHybrid reports whether the key bytes have the size and 0x06 (even y) or
+// 0x07 (odd y) prefix of an X9.62 hybrid point (prefix || X || Y)
+func (c *ecCurve) isHybrid(keyBytes []byte) bool {
+	return len(keyBytes) == 1+2*c.size && (keyBytes[0] == 0x06 || keyBytes[0] == 0x07)
+}
+
+// compressHybrid validates an X9.62 hybrid key, including that the parity of
+// the prefix matches the embedded y, and returns its compressed form
+func (c *ecCurve) compressHybrid(keyType KeyType, keyBytes []byte) ([]byte, error) {
+	x := new(big.Int).SetBytes(keyBytes[1 : 1+c.size])
+	y := new(big.Int).SetBytes(keyBytes[1+c.size:])
+	if !c.isOnCurve(x, y) {
+		return nil, ErrInvalidHybridPointWithContext(keyType)
+	}
+
+	if y.Bit(0) != uint(keyBytes[0]&1) {
+		return nil, ErrPointParityMismatchWithContext(keyType, keyBytes[0])
+	}
+
+	return c.compress(x, y), nil
+}
+
+// validateCompressedPrefix validates that compressed EC key bytes start with
+// the 0x02 or 0x03 parity byte. Key types without a curve are accepted as-is.
+func validateCompressedPrefix(keyType KeyType, keyBytes []byte) error {
+	if _, ok := curveFor(keyType); !ok {
+		return nil
+	}
+
+	if keyBytes[0] != 0x02 && keyBytes[0] != 0x03 {
+		return ErrInvalidCompressedPrefixWithContext(keyType, keyBytes[0])
+	}
+
+	return nil
+}
+
+// validatePoint validates that compressed EC key bytes encode a point on the
+// curve of the key type. Key types without a curve are accepted as-is.
+func validatePoint(keyType KeyType, keyBytes []byte) error {
+	curve, ok := curveFor(keyType)
+	if !ok {
+		return nil
+	}
+
+	_, _, err := curve.decompressPoint(keyBytes)
+	if errors.Is(err, errParityMismatch) {
+		return ErrPointParityMismatchWithContext(keyType, keyBytes[0])
+	}
+	if err != nil {
+		// secp256k1 and P-256 keys are both 33 bytes, so a key passed with the wrong
+		// type is only caught here; point out when the other curve accepts it
+		if other, ok := sameSizeKeyType(keyType); ok {
+			if otherCurve, _ := curveFor(other); otherCurve != nil {
+				if _, _, ok := otherCurve.decompress(keyBytes); ok {
+					return ErrInvalidPointWithHint(keyType, other)
+				}
+			}
+		}
+		return ErrInvalidPointWithContext(keyType)
+	}
+
+	return nil
+}
+
+// sameSizeKeyType returns the other EC key type whose compressed keys have
+// the same size and cannot be told apart without on-curve validation
+func sameSizeKeyType(keyType KeyType) (KeyType, bool) {
+	switch keyType {
+	case Secp256k1PublicKey:
+		return P256PublicKey, true
+	case P256PublicKey:
+		return Secp256k1PublicKey, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,475 @@
+package didkey
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/mr-tron/base58"
+)
+
+func TestEncodeECCoordinates(t *testing.T) {
+	t.Run("P-256 known vector", func(t *testing.T) {
+		x := mustDecodeHex("d0ef6c6209e4e3d0de5e555b9b3f7e3c5a4c7b1e9e2d8c3f4a5b6c7d8e9f01a0")
+		y := mustDecodeHex("fec9fb6ffffc5da7366e39d12d0ebafd2eac34866e1762d60bd0d5419b7ca958")
+
+		didKey, err := EncodeECCoordinates(P256PublicKey, x, y)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := testVectors["P-256-test"].didKey
+		if didKey != expected {
+			t.Errorf("Expected %s, got %s", expected, didKey)
+		}
+	})
+
+	curves := map[KeyType]ecdh.Curve{
+		P256PublicKey: ecdh.P256(),
+		P384PublicKey: ecdh.P384(),
+	}
+
+	for keyType, curve := range curves {
+		t.Run(keyType.String()+" generated", func(t *testing.T) {
+			privateKey, err := curve.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatalf("Failed to generate key: %v", err)
+			}
+
+			// Uncompressed form: 0x04 || X || Y
+			uncompressed := privateKey.PublicKey().Bytes()
+			size := (len(uncompressed) - 1) / 2
+			x, y := uncompressed[1:1+size], uncompressed[1+size:]
+
+			didKey, err := EncodeECCoordinates(keyType, x, y)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			decodedType, compressed, err := Decode(didKey)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+
+			if decodedType != keyType {
+				t.Errorf("Expected key type %s, got %s", keyType, decodedType)
+			}
+
+			if len(compressed) != size+1 {
+				t.Fatalf("Expected %d compressed bytes, got %d", size+1, len(compressed))
+			}
+
+			if expectedPrefix := 0x02 | y[len(y)-1]&1; compressed[0] != expectedPrefix {
+				t.Errorf("Expected prefix %#x, got %#x", expectedPrefix, compressed[0])
+			}
+
+			if hex.EncodeToString(compressed[1:]) != hex.EncodeToString(x) {
+				t.Errorf("Expected x %x, got %x", x, compressed[1:])
+			}
+		})
+	}
+
+	t.Run("point not on curve", func(t *testing.T) {
+		x := mustDecodeHex("d0ef6c6209e4e3d0de5e555b9b3f7e3c5a4c7b1e9e2d8c3f4a5b6c7d8e9f01a0")
+		y := mustDecodeHex("fec9fb6ffffc5da7366e39d12d0ebafd2eac34866e1762d60bd0d5419b7ca959")
+
+		_, err := EncodeECCoordinates(P256PublicKey, x, y)
+		if !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("Expected ErrInvalidPoint, got %v", err)
+		}
+	})
+
+	t.Run("wrong coordinate length", func(t *testing.T) {
+		_, err := EncodeECCoordinates(P384PublicKey, make([]byte, 32), make([]byte, 32))
+		if !errors.Is(err, ErrInvalidCoordinateSize) {
+			t.Errorf("Expected ErrInvalidCoordinateSize, got %v", err)
+		}
+	})
+
+	t.Run("non-EC key type", func(t *testing.T) {
+		_, err := EncodeECCoordinates(Ed25519PublicKey, make([]byte, 32), make([]byte, 32))
+		if !errors.Is(err, ErrUnsupportedKeyType) {
+			t.Errorf("Expected ErrUnsupportedKeyType, got %v", err)
+		}
+	})
+}
+
+func TestInvalidPointCrossCurveHint(t *testing.T) {
+	// secp256k1 generator point, which is not a valid P-256 point
+	secp256k1Key := mustDecodeHex("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	p256Key := mustDecodeHex(testVectors["P-256-test"].keyHex)
+
+	tests := []struct {
+		name     string
+		keyType  KeyType
+		keyBytes []byte
+		hint     string
+	}{
+		{
+			name:     "secp256k1 point as P-256",
+			keyType:  P256PublicKey,
+			keyBytes: secp256k1Key,
+			hint:     "valid secp256k1-pub point",
+		},
+		{
+			name:     "P-256 point as secp256k1",
+			keyType:  Secp256k1PublicKey,
+			keyBytes: p256Key,
+			hint:     "valid p256-pub point",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Encode(tt.keyType, tt.keyBytes)
+			if !errors.Is(err, ErrInvalidPoint) {
+				t.Fatalf("Expected ErrInvalidPoint, got %v", err)
+			}
+
+			if !strings.Contains(err.Error(), tt.hint) {
+				t.Errorf("Expected error to contain %q, got %q", tt.hint, err.Error())
+			}
+		})
+	}
+
+	t.Run("no hint when neither curve matches", func(t *testing.T) {
+		// x = 7 is on neither P-256 nor secp256k1
+		keyBytes := make([]byte, 33)
+		keyBytes[0] = 0x02
+		keyBytes[32] = 0x07
+
+		_, err := Encode(P256PublicKey, keyBytes)
+		if !errors.Is(err, ErrInvalidPoint) {
+			t.Fatalf("Expected ErrInvalidPoint, got %v", err)
+		}
+
+		if strings.Contains(err.Error(), "wrong key type") {
+			t.Errorf("Unexpected hint in %q", err.Error())
+		}
+	})
+}
+
+func TestInvalidCompressedPrefix(t *testing.T) {
+	keys := map[KeyType]string{
+		Secp256k1PublicKey: testVectors["Secp256k1-test"].keyHex,
+		P256PublicKey:      testVectors["P-256-test"].keyHex,
+		P384PublicKey:      p384GeneratorHex,
+	}
+
+	for keyType, keyHex := range keys {
+		for _, prefix := range []byte{0x00, 0x01, 0x04, 0x05, 0xff} {
+			t.Run(fmt.Sprintf("%s/%#02x", keyType, prefix), func(t *testing.T) {
+				keyBytes := mustDecodeHex(keyHex)
+				keyBytes[0] = prefix
+
+				// The prefix check is always on, even without curve validation
+				_, err := Encode(keyType, keyBytes, WithCurveValidation(false))
+				if !errors.Is(err, ErrInvalidCompressedPrefix) {
+					t.Fatalf("Expected ErrInvalidCompressedPrefix, got %v", err)
+				}
+
+				if expected := fmt.Sprintf("got %#02x", prefix); !strings.Contains(err.Error(), expected) {
+					t.Errorf("Expected error to contain %q, got %q", expected, err.Error())
+				}
+			})
+		}
+	}
+
+	t.Run("decode", func(t *testing.T) {
+		keyBytes := mustDecodeHex(testVectors["P-256-test"].keyHex)
+		keyBytes[0] = 0x05
+		didKey := DIDKeyPrefix + "z" + base58.Encode(encodeMulticodec(P256PublicKey, keyBytes))
+
+		if _, _, err := Decode(didKey, WithCurveValidation(false)); !errors.Is(err, ErrInvalidCompressedPrefix) {
+			t.Errorf("Expected ErrInvalidCompressedPrefix, got %v", err)
+		}
+	})
+}
+
+func TestUncompressedSecp256k1(t *testing.T) {
+	// secp256k1 generator point
+	uncompressed := mustDecodeHex("04" +
+		"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+		"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+	expected := "did:key:zQ3shVc2UkAfJCdc1TR8E66J85h48P43r93q8jGPkPpjF9Ef9"
+
+	t.Run("Encode", func(t *testing.T) {
+		didKey, err := Encode(Secp256k1PublicKey, uncompressed)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if didKey != expected {
+			t.Errorf("Expected %s, got %s", expected, didKey)
+		}
+	})
+
+	t.Run("FromBytes", func(t *testing.T) {
+		dk, err := FromBytes(Secp256k1PublicKey, uncompressed)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if dk.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, dk.String())
+		}
+
+		if len(dk.Bytes()) != 33 || dk.Bytes()[0] != 0x02 {
+			t.Errorf("Expected compressed key, got %x", dk.Bytes())
+		}
+	})
+
+	t.Run("bad prefix", func(t *testing.T) {
+		malformed := bytes.Clone(uncompressed)
+		malformed[0] = 0x05
+
+		if _, err := Encode(Secp256k1PublicKey, malformed); !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("Expected ErrInvalidPoint, got %v", err)
+		}
+	})
+
+	t.Run("not on curve", func(t *testing.T) {
+		malformed := bytes.Clone(uncompressed)
+		malformed[64] ^= 0x01
+
+		// Compressing an invalid point would silently produce a different key,
+		// so this is rejected even without curve validation
+		if _, err := FromBytes(Secp256k1PublicKey, malformed, WithCurveValidation(false)); !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("Expected ErrInvalidPoint, got %v", err)
+		}
+	})
+
+	t.Run("only secp256k1", func(t *testing.T) {
+		if _, err := Encode(P256PublicKey, make([]byte, 65)); !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected ErrInvalidKeySize, got %v", err)
+		}
+	})
+}
+
+func TestHybridEC(t *testing.T) {
+	// Generator points in X9.62 hybrid form: 0x06 for even y, 0x07 for odd y
+	tests := []struct {
+		name       string
+		keyType    KeyType
+		hybrid     string
+		compressed string
+	}{
+		{
+			name:    "secp256k1",
+			keyType: Secp256k1PublicKey,
+			hybrid: "06" +
+				"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" +
+				"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+			compressed: "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+		{
+			name:    "P-256",
+			keyType: P256PublicKey,
+			hybrid: "07" +
+				"6b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296" +
+				"4fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5",
+			compressed: "036b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296",
+		},
+		{
+			name:    "P-384",
+			keyType: P384PublicKey,
+			hybrid: "07" +
+				"aa87ca22be8b05378eb1c71ef320ad746e1d3b628ba79b9859f741e082542a385502f25dbf55296c3a545e3872760ab7" +
+				"3617de4a96262c6f5d9e98bf9292dc29f8f41dbd289a147ce9da3113b5f0b8c00a60b1ce1d7e819d7a431d7c90ea0e5f",
+			compressed: p384GeneratorHex,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hybrid := mustDecodeHex(tt.hybrid)
+
+			dk, err := FromBytes(tt.keyType, hybrid)
+			if err != nil {
+				t.Fatalf("FromBytes failed: %v", err)
+			}
+			if hex.EncodeToString(dk.Bytes()) != tt.compressed {
+				t.Errorf("Expected compressed key %s, got %x", tt.compressed, dk.Bytes())
+			}
+
+			expected, err := Encode(tt.keyType, mustDecodeHex(tt.compressed))
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			if didKey, err := Encode(tt.keyType, hybrid); err != nil || didKey != expected {
+				t.Errorf("Expected %s, got %s, %v", expected, didKey, err)
+			}
+
+			// The prefix claims the other parity of y
+			wrongParity := bytes.Clone(hybrid)
+			wrongParity[0] ^= 0x01
+			if _, err := Encode(tt.keyType, wrongParity); !errors.Is(err, ErrPointParityMismatch) {
+				t.Errorf("Expected ErrPointParityMismatch, got %v", err)
+			}
+
+			// Rejected even without curve validation, as compressing would
+			// silently produce a different key
+			notOnCurve := bytes.Clone(hybrid)
+			notOnCurve[len(notOnCurve)-1] ^= 0x02
+			if _, err := Encode(tt.keyType, notOnCurve, WithCurveValidation(false)); !errors.Is(err, ErrInvalidPoint) {
+				t.Errorf("Expected ErrInvalidPoint, got %v", err)
+			}
+		})
+	}
+}
+
+func TestECPointInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyType KeyType
+		keyHex  string
+		yOdd    bool
+	}{
+		{
+			name:    "secp256k1 odd",
+			keyType: Secp256k1PublicKey,
+			keyHex:  testVectors["Secp256k1-test"].keyHex,
+			yOdd:    true,
+		},
+		{
+			name:    "secp256k1 even",
+			keyType: Secp256k1PublicKey,
+			keyHex:  "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			yOdd:    false,
+		},
+		{
+			name:    "P-256 even",
+			keyType: P256PublicKey,
+			keyHex:  testVectors["P-256-test"].keyHex,
+			yOdd:    false,
+		},
+		{
+			name:    "P-384 odd",
+			keyType: P384PublicKey,
+			keyHex:  p384GeneratorHex,
+			yOdd:    true,
+		},
+		{
+			name:    "P-384 even",
+			keyType: P384PublicKey,
+			keyHex:  "02" + p384GeneratorHex[2:],
+			yOdd:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dk, err := FromBytes(tt.keyType, mustDecodeHex(tt.keyHex))
+			if err != nil {
+				t.Fatalf("FromBytes failed: %v", err)
+			}
+
+			compressed, yOdd, err := dk.ECPointInfo()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !compressed {
+				t.Errorf("Expected compressed key")
+			}
+
+			if yOdd != tt.yOdd {
+				t.Errorf("Expected yOdd %v, got %v", tt.yOdd, yOdd)
+			}
+
+			// The parity must match the decompressed y coordinate
+			curve, _ := curveFor(tt.keyType)
+			_, y, _ := curve.decompress(dk.Bytes())
+			if y.Bit(0) == 1 != yOdd {
+				t.Errorf("Parity disagrees with decompressed y %x", y)
+			}
+		})
+	}
+
+	t.Run("non-EC key", func(t *testing.T) {
+		dk, err := Parse(testVectors["Ed25519-from-spec"].didKey)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		if _, _, err := dk.ECPointInfo(); !errors.Is(err, ErrUnsupportedKeyType) {
+			t.Errorf("Expected ErrUnsupportedKeyType, got %v", err)
+		}
+	})
+}
+
+func TestDecompressParity(t *testing.T) {
+	// y² = x³ + x over GF(23) has the point (0, 0) of order 2, so x = 0 has only
+	// the even root y = 0. The curves of DID keys have prime order and no such
+	// point, so a toy curve is needed to present an odd-y prefix for it.
+	toy := &ecCurve{name: "toy", p: big.NewInt(23), a: big.NewInt(1), b: big.NewInt(0), size: 1}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		expectedY   int64
+		expectedErr error
+	}{
+		{name: "even root claimed even", data: []byte{0x02, 0x00}, expectedY: 0},
+		{name: "even root claimed odd", data: []byte{0x03, 0x00}, expectedErr: errParityMismatch},
+		{name: "two roots, even", data: []byte{0x02, 0x01}, expectedY: 18},
+		{name: "two roots, odd", data: []byte{0x03, 0x01}, expectedY: 5},
+		{name: "no root", data: []byte{0x02, 0x05}, expectedErr: errNotOnCurve},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, y, err := toy.Decompress(tt.data)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decompress failed: %v", err)
+			}
+			if y.Int64() != tt.expectedY {
+				t.Errorf("Expected y = %d, got %d", tt.expectedY, y)
+			}
+		})
+	}
+
+	t.Run("error wraps ErrInvalidPoint", func(t *testing.T) {
+		err := ErrPointParityMismatchWithContext(P256PublicKey, 0x03)
+		if !errors.Is(err, ErrPointParityMismatch) || !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("Expected ErrPointParityMismatch and ErrInvalidPoint, got %v", err)
+		}
+	})
+
+	t.Run("DID key curves honor both prefixes", func(t *testing.T) {
+		for _, didKey := range w3cTestVectors {
+			dk, err := Parse(didKey)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			keyType := dk.KeyType()
+			curve, ok := curveFor(keyType)
+			if !ok {
+				continue
+			}
+
+			for _, prefix := range []byte{0x02, 0x03} {
+				data := append([]byte{prefix}, dk.Bytes()[1:]...)
+				_, y, err := curve.decompressPoint(data)
+				if err != nil {
+					t.Fatalf("%s: decompressPoint failed for prefix %#02x: %v", keyType, prefix, err)
+				}
+				if y.Bit(0) != uint(prefix&1) {
+					t.Errorf("%s: expected y parity %d, got %d", keyType, prefix&1, y.Bit(0))
+				}
+			}
+		}
+	})
+}
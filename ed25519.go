@@ -0,0 +1,98 @@
+package didkey
+
+import (
+	"bytes"
+	"math/big"
+)
+
+// Field prime of Curve25519: 2^255 - 19
+var curve25519P = hexInt("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed")
+
+// ed25519ToX25519 converts an Ed25519 public key to the X25519 public key of
+// the birationally equivalent Montgomery curve, u = (1 + y) / (1 - y) mod p
+func ed25519ToX25519(keyBytes []byte) ([]byte, error) {
+	if len(keyBytes) != 32 {
+		return nil, ErrInvalidKeySizeWithContext(Ed25519PublicKey, 32, len(keyBytes))
+	}
+
+	// The key is the little-endian y coordinate with the sign of x in the top bit
+	le := make([]byte, 32)
+	copy(le, keyBytes)
+	le[31] &= 0x7f
+	y := new(big.Int).SetBytes(reverse(le))
+	if y.Cmp(curve25519P) >= 0 {
+		return nil, ErrInvalidPointWithContext(Ed25519PublicKey)
+	}
+
+	den := new(big.Int).Sub(big.NewInt(1), y)
+	den.Mod(den, curve25519P)
+	if den.Sign() == 0 {
+		return nil, ErrInvalidPointWithContext(Ed25519PublicKey)
+	}
+
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, den.ModInverse(den, curve25519P))
+	u.Mod(u, curve25519P)
+
+	return reverse(u.FillBytes(make([]byte, 32))), nil
+}
+
+// IsDerivedX25519 reports whether the X25519 DID key is the key agreement key
+// derived from the Ed25519 DID key, as listed under keyAgreement in the DID
+// Document of the Ed25519 key. Both DID keys are decoded and validated; either
+// having the wrong key type fails with ErrKeyTypeMismatch.
+func IsDerivedX25519(ed25519DIDKey, x25519DIDKey string) (bool, error) {
+	edKey, err := DecodeExpecting(ed25519DIDKey, Ed25519PublicKey)
+	if err != nil {
+		return false, err
+	}
+
+	xKey, err := DecodeExpecting(x25519DIDKey, X25519PublicKey)
+	if err != nil {
+		return false, err
+	}
+
+	derived, err := ed25519ToX25519(edKey)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(derived, xKey), nil
+}
+
+// ed25519SmallOrderY are the y coordinates of the eight Ed25519 points of
+// small order (the identity, and the points of order 2, 4 and 8)
+var ed25519SmallOrderY = []*big.Int{
+	big.NewInt(0),
+	big.NewInt(1),
+	new(big.Int).Sub(curve25519P, big.NewInt(1)),
+	hexInt("7a03ac9277fdc74ec6cc392cfa53202a0f67100d760b3cba4fd84d3d706a17c7"),
+	hexInt("05fc536d880238b13933c6d305acdfd5f098eff289f4c345b027b2c28f95e826"),
+}
+
+// validateEd25519Order rejects Ed25519 keys encoding a point of small order,
+// for which any signature verifies over some message set. Non-canonical
+// encodings (y >= p) are reduced first, so they cannot bypass the check.
+func validateEd25519Order(keyBytes []byte) error {
+	le := make([]byte, 32)
+	copy(le, keyBytes)
+	le[31] &= 0x7f
+	y := new(big.Int).SetBytes(reverse(le))
+	y.Mod(y, curve25519P)
+
+	for _, smallOrderY := range ed25519SmallOrderY {
+		if y.Cmp(smallOrderY) == 0 {
+			return ErrSmallOrderKeyWithContext(Ed25519PublicKey)
+		}
+	}
+
+	return nil
+}
+
+// reverse reverses a byte slice in place and returns it
+func reverse(b []byte) []byte {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
@@ -0,0 +1,93 @@
+package didkey
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsDerivedX25519(t *testing.T) {
+	// Derivation pair from the did:key specification
+	ed25519DID := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	x25519DID := "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"
+
+	tests := []struct {
+		name     string
+		ed25519  string
+		x25519   string
+		expected bool
+		err      error
+	}{
+		{
+			name:     "derived pair",
+			ed25519:  ed25519DID,
+			x25519:   x25519DID,
+			expected: true,
+		},
+		{
+			name:     "unrelated X25519 key",
+			ed25519:  ed25519DID,
+			x25519:   "did:key:z6LSeu9HkTHSfLLeUs2nnzUSNedgDUevfNQgQjQC23ZCit6F",
+			expected: false,
+		},
+		{
+			name:     "other Ed25519 key",
+			ed25519:  testVectors["Ed25519-test-1"].didKey,
+			x25519:   x25519DID,
+			expected: false,
+		},
+		{
+			name:    "swapped arguments",
+			ed25519: x25519DID,
+			x25519:  ed25519DID,
+			err:     ErrKeyTypeMismatch,
+		},
+		{
+			name:    "X25519 argument is Ed25519",
+			ed25519: ed25519DID,
+			x25519:  ed25519DID,
+			err:     ErrKeyTypeMismatch,
+		},
+		{
+			name:    "invalid DID key",
+			ed25519: "did:key:invalid",
+			x25519:  x25519DID,
+			err:     ErrInvalidFingerprintLength,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			derived, err := IsDerivedX25519(tt.ed25519, tt.x25519)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("Expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if derived != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, derived)
+			}
+		})
+	}
+
+	t.Run("matches resolved keyAgreement", func(t *testing.T) {
+		doc, err := ResolveDocument(ed25519DID)
+		if err != nil {
+			t.Fatalf("ResolveDocument failed: %v", err)
+		}
+
+		x25519, err := doc.VerificationMethod[1].PublicKey()
+		if err != nil {
+			t.Fatalf("PublicKey failed: %v", err)
+		}
+
+		if derived, err := IsDerivedX25519(ed25519DID, x25519.String()); err != nil || !derived {
+			t.Errorf("Expected the keyAgreement key to be derived, got %v, %v", derived, err)
+		}
+	})
+}
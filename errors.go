@@ -1,33 +1,128 @@
 package didkey
 
 import (
+	"crypto"
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/multiformats/go-multibase"
 )
 
 var (
 	// Encoding errors
-	ErrEmptyKeyBytes         = errors.New("key bytes cannot be empty")
+	ErrEmptyKeyBytes = errors.New("key bytes cannot be empty")
+
+	// Deprecated: base58-btc encoding cannot fail, so Encode never returns this error.
 	ErrMultibaseEncodeFailed = errors.New("failed to encode multibase")
 
 	// Decoding errors
-	ErrEmptyMultibaseString  = errors.New("empty multibase string")
-	ErrInvalidDIDKeyPrefix   = errors.New("invalid DID key prefix")
-	ErrExpectedBase58BTC     = errors.New("expected base58-btc encoding")
-	ErrEmptyData             = errors.New("empty data")
-	ErrInvalidVarint         = errors.New("invalid varint")
-	ErrNoKeyDataAfterVarint  = errors.New("no key data after varint")
-	ErrMultibaseDecodeFailed = errors.New("failed to decode multibase")
+	ErrEmptyMultibaseString     = errors.New("empty multibase string")
+	ErrInvalidUTF8              = errors.New("DID key is not valid UTF-8")
+	ErrControlCharacter         = errors.New("DID key contains a control character")
+	ErrNonASCII                 = errors.New("DID key contains a non-ASCII character")
+	ErrInvalidFingerprintLength = errors.New("invalid fingerprint length")
+	ErrFingerprintTooLong       = errors.New("fingerprint too long")
+	ErrInvalidDIDKeyPrefix      = errors.New("invalid DID key prefix")
+	ErrExpectedBase58BTC        = errors.New("expected base58-btc encoding")
+	ErrEmptyData                = errors.New("empty data")
+	ErrInvalidVarint            = errors.New("invalid varint")
+	ErrNoKeyDataAfterVarint     = errors.New("no key data after varint")
+	ErrMultibaseDecodeFailed    = errors.New("failed to decode multibase")
+	ErrInvalidBase58Character   = errors.New("invalid base58 character")
+	ErrUnknownMultibasePrefix   = errors.New("unknown multibase prefix")
+
+	ErrFingerprintPrefixMismatch = errors.New("fingerprint prefix does not match key type")
 
 	// Validation errors (used by both encoding and decoding)
 	ErrUnsupportedKeyType = errors.New("unsupported key type")
 	ErrInvalidKeySize     = errors.New("invalid key size")
+	ErrUnknownKeyTypeName = errors.New("unknown key type name")
+	ErrKeyTypeMismatch    = errors.New("unexpected key type")
+	ErrInvalidHex         = errors.New("invalid hex key")
+	ErrKeyTypeNotInferred = errors.New("cannot infer key type from key size")
+	ErrNilDIDKey          = errors.New("DID key is nil")
+
+	ErrInvalidSafetyWordCount = errors.New("invalid safety word count")
+
+	// Batch errors
+	ErrInvalidJSONArray = errors.New("invalid JSON array of DID keys")
+	ErrInputTooLarge    = errors.New("batch input too large")
+	ErrLineTooLong      = errors.New("batch input line too long")
+
+	// Credential errors
+	ErrInvalidCredential   = errors.New("invalid credential")
+	ErrUnsupportedProof    = errors.New("unsupported proof")
+	ErrInvalidProof        = errors.New("invalid proof")
+	ErrIssuerMismatch      = errors.New("proof verification method does not belong to the issuer")
+	ErrCryptosuiteMismatch = errors.New("cryptosuite does not match key type")
+
+	ErrNoDataIntegrityCryptosuite = errors.New("key type has no Data Integrity cryptosuite")
+
+	// Stream errors
+	ErrFrameTooLarge = errors.New("DID key frame too large")
+
+	// Filter errors
+	ErrKeyTypeNotAllowed = errors.New("key type not allowed")
+
+	// EC point errors
+	ErrInvalidCompressedPrefix = errors.New("invalid compressed point prefix")
+	ErrInvalidCoordinateSize   = errors.New("invalid coordinate size")
+	ErrInvalidPoint            = errors.New("point is not on curve")
+	ErrSmallOrderKey           = errors.New("key is a point of small order")
+	ErrAllZeroKey              = errors.New("key bytes are all zero")
+	ErrPointParityMismatch     = errors.New("no point on curve with the y parity of the compressed prefix")
+	ErrInvalidBLSEncoding      = errors.New("invalid BLS12-381 compressed point encoding")
+
+	// Conversion errors
+	ErrJWKUnsupported                     = errors.New("key type has no JWK representation")
+	ErrInvalidJWK                         = errors.New("invalid JWK")
+	ErrInvalidPKCS8                       = errors.New("invalid PKCS #8 private key")
+	ErrCOSEUnsupported                    = errors.New("key type has no COSE_Key representation")
+	ErrIncompatibleVerificationMethodType = errors.New("key type cannot be represented by verification method type")
+
+	// Signature verification errors
+	ErrVerificationUnsupported = errors.New("signature verification not supported")
+	ErrBLSUnavailable          = errors.New("BLS signature verification requires the bls build tag")
+	ErrUnsupportedHash         = errors.New("unsupported signature hash")
+
+	// Signing errors
+	ErrUnsupportedPrivateKey = errors.New("unsupported private key type")
+	ErrEmptySeed             = errors.New("seed cannot be empty")
+	// JWS errors
+	ErrInvalidJWS        = errors.New("invalid JWS")
+	ErrAlgorithmMismatch = errors.New("JWS algorithm does not match key type")
+	ErrInvalidSignature  = errors.New("invalid signature")
+
+	// Verification method URI errors
+	ErrInvalidVerificationMethodURI = errors.New("invalid verification method URI")
+	ErrFragmentMismatch             = errors.New("fragment does not match the DID key fingerprint")
+	ErrNotSigningKey                = errors.New("key type cannot be used for signing")
+	ErrKeyAgreementKeyNotSignable   = errors.New("key agreement key cannot be used for signatures")
+
+	// Document errors
+	ErrInvalidDocument                   = errors.New("invalid DID document")
+	ErrNoVerificationMethods             = errors.New("DID document has no verification methods")
+	ErrUnsupportedVerificationMethodType = errors.New("unsupported verification method type")
+	ErrInvalidControllerDID              = errors.New("invalid controller DID")
+
+	// DID URL errors
+	ErrInvalidDIDURL               = errors.New("invalid DID URL")
+	ErrMatrixParametersUnsupported = errors.New("DID URL matrix parameters are not supported")
+
+	// Resolution errors
+	ErrVersioningUnsupported  = errors.New("did:key documents are immutable and have no versions")
+	ErrMissingServiceParam    = errors.New("DID URL has no service parameter")
+	ErrServiceNotFound        = errors.New("service not found")
+	ErrDocumentMismatch       = errors.New("DID Document does not belong to the DID")
+	ErrInvalidServiceEndpoint = errors.New("service endpoint is not an absolute URL")
 )
 
 func ErrInvalidDIDKeyPrefixWithContext(expected string) error {
 	return fmt.Errorf("%w, expected '%s'", ErrInvalidDIDKeyPrefix, expected)
 }
 
+// Deprecated: base58-btc encoding cannot fail, so Encode never returns this error.
 func ErrMultibaseEncodeFailedWithContext(err error) error {
 	return fmt.Errorf("%w: %w", ErrMultibaseEncodeFailed, err)
 }
@@ -47,3 +142,257 @@ func ErrUnsupportedKeyTypeWithContext(keyType KeyType) error {
 func ErrInvalidKeySizeWithContext(keyType KeyType, expected, actual int) error {
 	return fmt.Errorf("%w for %s: expected %d bytes, got %d", ErrInvalidKeySize, keyType, expected, actual)
 }
+
+func ErrInvalidCoordinateSizeWithContext(keyType KeyType, expected, actualX, actualY int) error {
+	return fmt.Errorf("%w for %s: expected %d bytes, got x=%d y=%d", ErrInvalidCoordinateSize, keyType, expected, actualX, actualY)
+}
+
+func ErrInvalidPointWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w for %s", ErrInvalidPoint, keyType)
+}
+
+func ErrInvalidPointWithHint(keyType, likely KeyType) error {
+	return fmt.Errorf("%w for %s, but it is a valid %s point: wrong key type?", ErrInvalidPoint, keyType, likely)
+}
+
+func ErrJWKUnsupportedWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w: %s", ErrJWKUnsupported, keyType)
+}
+
+func ErrVerificationUnsupportedWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w for %s", ErrVerificationUnsupported, keyType)
+}
+
+func ErrVersioningUnsupportedWithContext(param string) error {
+	return fmt.Errorf("%w: unexpected %s parameter", ErrVersioningUnsupported, param)
+}
+
+func ErrKeyTypeNotAllowedWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w: %s", ErrKeyTypeNotAllowed, keyType)
+}
+
+func ErrInvalidCompressedPrefixWithContext(keyType KeyType, prefix byte) error {
+	return fmt.Errorf("%w for %s: expected 0x02 or 0x03, got %#02x", ErrInvalidCompressedPrefix, keyType, prefix)
+}
+
+func ErrInvalidDocumentWithContext(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+}
+
+func ErrUnsupportedVerificationMethodTypeWithContext(vmType string) error {
+	return fmt.Errorf("%w: %s", ErrUnsupportedVerificationMethodType, vmType)
+}
+
+func ErrFrameTooLargeWithContext(length uint64, limit int) error {
+	return fmt.Errorf("%w: %d bytes, limit is %d", ErrFrameTooLarge, length, limit)
+}
+
+func ErrInvalidBLSEncodingWithContext(keyType KeyType, reason string) error {
+	return fmt.Errorf("%w for %s: %s", ErrInvalidBLSEncoding, keyType, reason)
+}
+
+// ErrUncompressedBLSKeyWithContext also matches ErrInvalidKeySize
+func ErrUncompressedBLSKeyWithContext(keyType KeyType, expected, actual int) error {
+	return fmt.Errorf("%w for %s: %w: got a %d-byte uncompressed point, expected the %d-byte compressed form", ErrInvalidBLSEncoding, keyType, ErrInvalidKeySize, actual, expected)
+}
+
+func ErrInvalidUncompressedPointWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w for %s: malformed uncompressed key", ErrInvalidPoint, keyType)
+}
+
+func ErrUnknownKeyTypeNameWithContext(name string) error {
+	return fmt.Errorf("%w: %q", ErrUnknownKeyTypeName, name)
+}
+
+func ErrFingerprintPrefixMismatchWithContext(keyType KeyType, expected, fingerprint string) error {
+	return fmt.Errorf("%w: expected %s fingerprint to start with %s, got %s", ErrFingerprintPrefixMismatch, keyType, expected, fingerprint)
+}
+
+func ErrInvalidVerificationMethodURIWithContext(uri string) error {
+	return fmt.Errorf("%w: expected did:key:<fingerprint>#<fingerprint>, got %s", ErrInvalidVerificationMethodURI, uri)
+}
+
+func ErrFragmentMismatchWithContext(fingerprint, fragment string) error {
+	return fmt.Errorf("%w: expected #%s, got #%s", ErrFragmentMismatch, fingerprint, fragment)
+}
+
+func ErrNotSigningKeyWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w: %s", ErrNotSigningKey, keyType)
+}
+
+// ErrKeyAgreementKeyNotSignableWithContext also matches ErrNotSigningKey
+func ErrKeyAgreementKeyNotSignableWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w: %w: %s", ErrKeyAgreementKeyNotSignable, ErrNotSigningKey, keyType)
+}
+
+func ErrInvalidDIDURLWithContext(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidDIDURL, err)
+}
+
+func ErrMatrixParametersUnsupportedWithContext(params string) error {
+	return fmt.Errorf("%w: %w: ;%s; did:key DIDs are generative and take no parameters, use query parameters for DID URL parameters", ErrInvalidDIDURL, ErrMatrixParametersUnsupported, params)
+}
+
+func ErrInvalidJWKWithContext(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidJWK, reason)
+}
+
+func ErrInvalidPKCS8WithContext(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidPKCS8, err)
+}
+
+func ErrIncompatibleVerificationMethodTypeWithContext(keyType KeyType, vmType string) error {
+	return fmt.Errorf("%w: %s as %s", ErrIncompatibleVerificationMethodType, keyType, vmType)
+}
+
+func ErrServiceNotFoundWithContext(service string) error {
+	return fmt.Errorf("%w: %s", ErrServiceNotFound, service)
+}
+
+func ErrDocumentMismatchWithContext(did, documentID string) error {
+	return fmt.Errorf("%w: expected %s, got %s", ErrDocumentMismatch, did, documentID)
+}
+
+func ErrInvalidServiceEndpointWithContext(endpoint string, err error) error {
+	if err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrInvalidServiceEndpoint, endpoint, err)
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidServiceEndpoint, endpoint)
+}
+
+func ErrInvalidFingerprintLengthWithContext(length, minLength, maxLength int) error {
+	return fmt.Errorf("%w: %d characters, expected %d to %d", ErrInvalidFingerprintLength, length, minLength, maxLength)
+}
+
+func ErrFingerprintTooLongWithContext(length, maxLength int) error {
+	return fmt.Errorf("%w: %w: %d characters, at most %d", ErrInvalidFingerprintLength, ErrFingerprintTooLong, length, maxLength)
+}
+
+func ErrKeyTypeMismatchWithContext(expected, actual KeyType) error {
+	return fmt.Errorf("%w: expected %s, got %s", ErrKeyTypeMismatch, expected, actual)
+}
+
+func ErrInvalidHexWithContext(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidHex, err)
+}
+
+// ErrKeyTypeNotInferredWithContext lists the key types of the size, if any
+func ErrKeyTypeNotInferredWithContext(size int, candidates []KeyType) error {
+	if len(candidates) == 0 {
+		return fmt.Errorf("%w: no key type has %d-byte keys", ErrKeyTypeNotInferred, size)
+	}
+
+	names := make([]string, len(candidates))
+	for i, keyType := range candidates {
+		names[i] = KeyTypeName(keyType)
+	}
+	return fmt.Errorf("%w: %d-byte keys could be %s, specify the key type", ErrKeyTypeNotInferred, size, strings.Join(names, " or "))
+}
+
+func ErrInvalidSafetyWordCountWithContext(count, maxCount int) error {
+	return fmt.Errorf("%w: %d, expected 1 to %d", ErrInvalidSafetyWordCount, count, maxCount)
+}
+
+func ErrControlCharacterWithContext(index int, char byte) error {
+	return fmt.Errorf("%w: %#02x at index %d", ErrControlCharacter, char, index)
+}
+
+func ErrExpectedBase58BTCWithContext(encoding multibase.Encoding) error {
+	name, ok := multibase.EncodingToStr[encoding]
+	if !ok {
+		name = "unknown encoding"
+	}
+	return fmt.Errorf("%w, got %s ('%c')", ErrExpectedBase58BTC, name, rune(encoding))
+}
+
+func ErrCOSEUnsupportedWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w: %s", ErrCOSEUnsupported, keyType)
+}
+
+func ErrSmallOrderKeyWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w: %s", ErrSmallOrderKey, keyType)
+}
+
+func ErrInvalidJWSWithContext(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidJWS, reason)
+}
+
+func ErrAlgorithmMismatchWithContext(alg string, keyType KeyType) error {
+	return fmt.Errorf("%w: %q cannot be verified by a %s key", ErrAlgorithmMismatch, alg, keyType)
+}
+
+func ErrUnsupportedPrivateKeyWithContext(priv any) error {
+	return fmt.Errorf("%w: %T", ErrUnsupportedPrivateKey, priv)
+}
+
+func ErrUnsupportedPublicKeyWithContext(pub any) error {
+	return fmt.Errorf("%w: public key %T", ErrUnsupportedKeyType, pub)
+}
+
+func ErrInvalidBase58CharacterWithContext(char rune) error {
+	return fmt.Errorf("%w %q", ErrInvalidBase58Character, char)
+}
+
+func ErrUnknownMultibasePrefixWithContext(prefix rune) error {
+	return fmt.Errorf("%w %q", ErrUnknownMultibasePrefix, prefix)
+}
+
+func ErrAllZeroKeyWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w: %s", ErrAllZeroKey, keyType)
+}
+
+func ErrInvalidJSONArrayWithContext(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidJSONArray, err)
+}
+
+func ErrInputTooLargeWithContext(limit int64) error {
+	return fmt.Errorf("%w: more than %d bytes", ErrInputTooLarge, limit)
+}
+
+func ErrLineTooLongWithContext(line, limit int) error {
+	return fmt.Errorf("%w: line %d is longer than %d bytes", ErrLineTooLong, line, limit)
+}
+
+func ErrPointParityMismatchWithContext(keyType KeyType, prefix byte) error {
+	return fmt.Errorf("%w for %s: %w %#02x", ErrInvalidPoint, keyType, ErrPointParityMismatch, prefix)
+}
+
+func ErrInvalidControllerDIDWithContext(controller string) error {
+	return fmt.Errorf("%w: %q", ErrInvalidControllerDID, controller)
+}
+
+func ErrInvalidHybridPointWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w for %s: malformed hybrid key", ErrInvalidPoint, keyType)
+}
+
+func ErrInvalidCredentialWithContext(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidCredential, reason)
+}
+
+func ErrUnsupportedProofWithContext(reason string) error {
+	return fmt.Errorf("%w: %s", ErrUnsupportedProof, reason)
+}
+
+func ErrInvalidProofWithContext(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidProof, reason)
+}
+
+func ErrIssuerMismatchWithContext(issuer, did string) error {
+	return fmt.Errorf("%w: issuer is %s, verification method DID is %s", ErrIssuerMismatch, issuer, did)
+}
+
+func ErrCryptosuiteMismatchWithContext(cryptosuite string, keyType KeyType) error {
+	return fmt.Errorf("%w: %s cannot be used with a %s key", ErrCryptosuiteMismatch, cryptosuite, keyType)
+}
+
+func ErrNonASCIIWithContext(index int, char rune) error {
+	return fmt.Errorf("%w: %q (%U) at index %d", ErrNonASCII, char, char, index)
+}
+
+func ErrNoDataIntegrityCryptosuiteWithContext(keyType KeyType) error {
+	return fmt.Errorf("%w: %s", ErrNoDataIntegrityCryptosuite, keyType)
+}
+
+func ErrUnsupportedHashWithContext(hash crypto.Hash, reason string) error {
+	return fmt.Errorf("%w %s: %s", ErrUnsupportedHash, hash, reason)
+}
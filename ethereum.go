@@ -0,0 +1,52 @@
+package didkey
+
+import (
+	"encoding/hex"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// EthereumAddress returns the EIP-55 checksummed Ethereum address of a
+// secp256k1 DID key: the last 20 bytes of the Keccak-256 hash of the
+// uncompressed public key X || Y. It returns ErrUnsupportedKeyType for other
+// key types.
+func (dk *DIDKey) EthereumAddress() (string, error) {
+	if dk.keyType != Secp256k1PublicKey {
+		return "", ErrUnsupportedKeyTypeWithContext(dk.keyType)
+	}
+
+	x, y, ok := secp256k1Curve.decompress(dk.keyBytes)
+	if !ok {
+		return "", ErrInvalidPointWithContext(dk.keyType)
+	}
+
+	uncompressed := make([]byte, 2*secp256k1Curve.size)
+	x.FillBytes(uncompressed[:secp256k1Curve.size])
+	y.FillBytes(uncompressed[secp256k1Curve.size:])
+
+	return "0x" + eip55Checksum(hex.EncodeToString(keccak256(uncompressed)[12:])), nil
+}
+
+// eip55Checksum uppercases the letters of a lowercase hex address whose
+// corresponding nibble of the Keccak-256 hash of the address is 8 or more
+func eip55Checksum(address string) string {
+	hash := keccak256([]byte(address))
+	out := []byte(address)
+	for i, c := range out {
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0x0f
+		}
+		if c >= 'a' && nibble >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return string(out)
+}
+
+// keccak256 is the original Keccak-256 used by Ethereum, which differs from SHA3-256 in padding
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,56 @@
+package didkey
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEthereumAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyHex   string
+		expected string
+	}{
+		{
+			// Public key of private key 1
+			name:     "generator",
+			keyHex:   "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			expected: "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		},
+		{
+			// Public key of private key 2
+			name:     "2G",
+			keyHex:   "02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+			expected: "0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dk, err := FromBytes(Secp256k1PublicKey, mustDecodeHex(tt.keyHex))
+			if err != nil {
+				t.Fatalf("FromBytes failed: %v", err)
+			}
+
+			address, err := dk.EthereumAddress()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if address != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, address)
+			}
+		})
+	}
+
+	t.Run("non-secp256k1 key", func(t *testing.T) {
+		dk, err := Parse(testVectors["P-256-test"].didKey)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		if _, err := dk.EthereumAddress(); !errors.Is(err, ErrUnsupportedKeyType) {
+			t.Errorf("Expected ErrUnsupportedKeyType, got %v", err)
+		}
+	})
+}
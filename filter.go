@@ -0,0 +1,59 @@
+package didkey
+
+// TypeFilter accepts or rejects DID keys by key type. The zero value accepts
+// every supported key type; Allow restricts it to an allow-list and Deny
+// rejects specific key types regardless of the allow-list.
+type TypeFilter struct {
+	allowed map[KeyType]struct{}
+	denied  map[KeyType]struct{}
+}
+
+// Allow adds key types to the allow-list. Once any key type is allowed, all
+// other key types are rejected.
+func (f *TypeFilter) Allow(keyTypes ...KeyType) *TypeFilter {
+	if f.allowed == nil {
+		f.allowed = make(map[KeyType]struct{})
+	}
+	for _, keyType := range keyTypes {
+		f.allowed[keyType] = struct{}{}
+	}
+	return f
+}
+
+// Deny adds key types to the deny-list
+func (f *TypeFilter) Deny(keyTypes ...KeyType) *TypeFilter {
+	if f.denied == nil {
+		f.denied = make(map[KeyType]struct{})
+	}
+	for _, keyType := range keyTypes {
+		f.denied[keyType] = struct{}{}
+	}
+	return f
+}
+
+// Allows reports whether the filter accepts the key type
+func (f *TypeFilter) Allows(keyType KeyType) bool {
+	if _, ok := f.denied[keyType]; ok {
+		return false
+	}
+	if f.allowed == nil {
+		return true
+	}
+	_, ok := f.allowed[keyType]
+	return ok
+}
+
+// Check decodes a DID key and returns an error if it is invalid or its key
+// type is not accepted by the filter
+func (f *TypeFilter) Check(didKey string, opts ...Option) error {
+	keyType, _, err := Decode(didKey, opts...)
+	if err != nil {
+		return err
+	}
+
+	if !f.Allows(keyType) {
+		return ErrKeyTypeNotAllowedWithContext(keyType)
+	}
+
+	return nil
+}
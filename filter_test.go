@@ -0,0 +1,62 @@
+package didkey
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTypeFilter(t *testing.T) {
+	ed25519DID := testVectors["Ed25519-from-spec"].didKey
+	x25519DID := "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"
+	p256DID := testVectors["P-256-test"].didKey
+
+	t.Run("Ed25519 only", func(t *testing.T) {
+		filter := new(TypeFilter).Allow(Ed25519PublicKey)
+
+		if err := filter.Check(ed25519DID); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+
+		if err := filter.Check(x25519DID); !errors.Is(err, ErrKeyTypeNotAllowed) {
+			t.Errorf("Expected ErrKeyTypeNotAllowed, got %v", err)
+		}
+	})
+
+	t.Run("multiple allowed", func(t *testing.T) {
+		filter := new(TypeFilter).Allow(Ed25519PublicKey, P256PublicKey)
+
+		for _, didKey := range []string{ed25519DID, p256DID} {
+			if err := filter.Check(didKey); err != nil {
+				t.Errorf("Unexpected error for %s: %v", didKey, err)
+			}
+		}
+	})
+
+	t.Run("zero value accepts all", func(t *testing.T) {
+		var filter TypeFilter
+		for _, didKey := range []string{ed25519DID, x25519DID, p256DID} {
+			if err := filter.Check(didKey); err != nil {
+				t.Errorf("Unexpected error for %s: %v", didKey, err)
+			}
+		}
+	})
+
+	t.Run("deny overrides allow", func(t *testing.T) {
+		filter := new(TypeFilter).Allow(Ed25519PublicKey, X25519PublicKey).Deny(X25519PublicKey)
+
+		if err := filter.Check(x25519DID); !errors.Is(err, ErrKeyTypeNotAllowed) {
+			t.Errorf("Expected ErrKeyTypeNotAllowed, got %v", err)
+		}
+
+		if !filter.Allows(Ed25519PublicKey) {
+			t.Errorf("Expected Ed25519 to be allowed")
+		}
+	})
+
+	t.Run("invalid DID key", func(t *testing.T) {
+		filter := new(TypeFilter).Allow(Ed25519PublicKey)
+		if err := filter.Check("did:web:example.com"); !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+			t.Errorf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+		}
+	})
+}
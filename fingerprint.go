@@ -0,0 +1,106 @@
+package didkey
+
+import (
+	"strings"
+
+	"github.com/multiformats/go-varint"
+)
+
+// fingerprintPrefixes are the leading characters shared by every valid
+// fingerprint of a key type, as listed in the DID Key specification. They
+// follow from the multicodec prefix and the fixed key size.
+var fingerprintPrefixes = map[KeyType]string{
+	Ed25519PublicKey:    "z6Mk",
+	X25519PublicKey:     "z6LS",
+	Secp256k1PublicKey:  "zQ3s",
+	Bls12381G1PublicKey: "z3tE",
+	Bls12381G2PublicKey: "zUC", // zUC6 or zUC7 depending on the key
+	P256PublicKey:       "zDna",
+	P384PublicKey:       "z82L",
+}
+
+// FingerprintPrefix returns the leading characters shared by every fingerprint
+// of the key type, e.g. "z6Mk" for Ed25519
+func FingerprintPrefix(keyType KeyType) (string, bool) {
+	prefix, ok := fingerprintPrefixes[keyType]
+	return prefix, ok
+}
+
+// ValidateFingerprintPrefix checks that a DID key's fingerprint starts with
+// the prefix expected for the key type encoded in it. This is a fast integrity
+// heuristic: it reads the key type without validating the key bytes, and
+// catches identifiers whose key bytes were truncated or extended in a way that
+// still decodes.
+func ValidateFingerprintPrefix(didKey string) error {
+	multicodecBytes, err := decodeDIDKey(didKey)
+	if err != nil {
+		return err
+	}
+
+	keyType, err := multicodecKeyType(multicodecBytes)
+	if err != nil {
+		return err
+	}
+	expected := fingerprintPrefixes[keyType]
+
+	fingerprint := didKey[len(DIDKeyPrefix):]
+	if !strings.HasPrefix(fingerprint, expected) {
+		return ErrFingerprintPrefixMismatchWithContext(keyType, expected, fingerprint)
+	}
+
+	return nil
+}
+
+// VerifyFingerprintKeyType decodes a multibase fingerprint, such as the
+// publicKeyMultibase of a Multikey verification method, and checks that it
+// holds a valid key of the expected type. A fingerprint of another key type
+// fails with ErrKeyTypeMismatch.
+func VerifyFingerprintKeyType(fingerprint string, expected KeyType, opts ...Option) error {
+	keyType, _, err := Decode(DIDKeyPrefix+fingerprint, opts...)
+	if err != nil {
+		return err
+	}
+
+	if keyType != expected {
+		return ErrKeyTypeMismatchWithContext(expected, keyType)
+	}
+
+	return nil
+}
+
+// FingerprintKeyType returns the key type of a multibase fingerprint, such as
+// "z6Mk...", reading only its multicodec varint. It is meant for bulk
+// classification: the key bytes are not validated at all, neither their size
+// nor their point, so a valid key type does not mean a usable key. Base58 is
+// not prefix-decodable, so the whole fingerprint is still decoded, but only
+// fingerprints up to MaxFingerprintChars characters long.
+func FingerprintKeyType(fingerprint string) (KeyType, error) {
+	if fingerprint == "" {
+		return 0, ErrEmptyMultibaseString
+	}
+	if len(fingerprint) > MaxFingerprintChars {
+		return 0, ErrFingerprintTooLongWithContext(len(fingerprint), MaxFingerprintChars)
+	}
+
+	multicodecBytes, err := decodeMultibase(fingerprint)
+	if err != nil {
+		return 0, err
+	}
+
+	return multicodecKeyType(multicodecBytes)
+}
+
+// multicodecKeyType reads the supported key type from the varint of
+// multicodec-prefixed key bytes, ignoring the key bytes
+func multicodecKeyType(multicodecBytes []byte) (KeyType, error) {
+	value, _, err := varint.FromUvarint(multicodecBytes)
+	if err != nil {
+		return 0, ErrInvalidVarintWithContext(err)
+	}
+
+	keyType := KeyType(value)
+	if _, ok := fingerprintPrefixes[keyType]; !ok {
+		return 0, ErrUnsupportedKeyTypeWithContext(keyType)
+	}
+	return keyType, nil
+}
@@ -0,0 +1,176 @@
+package didkey
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/mr-tron/base58"
+)
+
+func TestFingerprintPrefixes(t *testing.T) {
+	tests := []struct {
+		keyType KeyType
+		prefix  string
+		keys    [][]byte
+	}{
+		{Ed25519PublicKey, "z6Mk", [][]byte{make([]byte, 32), bytes.Repeat([]byte{0xff}, 32)}},
+		{X25519PublicKey, "z6LS", [][]byte{make([]byte, 32), bytes.Repeat([]byte{0xff}, 32)}},
+		{Secp256k1PublicKey, "zQ3s", [][]byte{mustDecodeHex(testVectors["Secp256k1-test"].keyHex)}},
+		{Bls12381G1PublicKey, "z3tE", [][]byte{blsPlaceholderKey(48), append([]byte{0xbf}, bytes.Repeat([]byte{0xff}, 47)...)}},
+		{Bls12381G2PublicKey, "zUC", [][]byte{blsPlaceholderKey(96), append([]byte{0xbf}, bytes.Repeat([]byte{0xff}, 95)...)}},
+		{P256PublicKey, "zDna", [][]byte{mustDecodeHex(testVectors["P-256-test"].keyHex)}},
+		{P384PublicKey, "z82L", [][]byte{mustDecodeHex(p384GeneratorHex)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keyType.String(), func(t *testing.T) {
+			prefix, ok := FingerprintPrefix(tt.keyType)
+			if !ok || prefix != tt.prefix {
+				t.Fatalf("Expected prefix %s, got %s", tt.prefix, prefix)
+			}
+
+			for _, keyBytes := range tt.keys {
+				didKey, err := Encode(tt.keyType, keyBytes)
+				if err != nil {
+					t.Fatalf("Encode failed: %v", err)
+				}
+
+				if !strings.HasPrefix(didKey, DIDKeyPrefix+tt.prefix) {
+					t.Errorf("Expected %s to start with %s", didKey, tt.prefix)
+				}
+
+				if err := ValidateFingerprintPrefix(didKey); err != nil {
+					t.Errorf("Unexpected error for %s: %v", didKey, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateFingerprintPrefixMismatch(t *testing.T) {
+	// An Ed25519 code followed by a key with extra bytes still decodes as
+	// base58, but no longer has the z6Mk prefix
+	didKey := DIDKeyPrefix + "z" + base58.Encode(encodeMulticodec(Ed25519PublicKey, make([]byte, 40)))
+
+	if err := ValidateFingerprintPrefix(didKey); !errors.Is(err, ErrFingerprintPrefixMismatch) {
+		t.Errorf("Expected ErrFingerprintPrefixMismatch for %s, got %v", didKey, err)
+	}
+
+	if err := ValidateFingerprintPrefix("did:web:example.com"); !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+		t.Errorf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+	}
+}
+
+func TestVerifyFingerprintKeyType(t *testing.T) {
+	tests := []struct {
+		name        string
+		fingerprint string
+		expected    KeyType
+		err         error
+	}{
+		{
+			name:        "Ed25519",
+			fingerprint: "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			expected:    Ed25519PublicKey,
+		},
+		{
+			name:        "P-256",
+			fingerprint: testVectors["P-256-test"].didKey[len(DIDKeyPrefix):],
+			expected:    P256PublicKey,
+		},
+		{
+			name:        "X25519 claimed as Ed25519",
+			fingerprint: "z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p",
+			expected:    Ed25519PublicKey,
+			err:         ErrKeyTypeMismatch,
+		},
+		{
+			name:        "secp256k1 claimed as P-256",
+			fingerprint: testVectors["Secp256k1-test"].didKey[len(DIDKeyPrefix):],
+			expected:    P256PublicKey,
+			err:         ErrKeyTypeMismatch,
+		},
+		{
+			name:        "full DID key",
+			fingerprint: "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			expected:    Ed25519PublicKey,
+			err:         ErrMultibaseDecodeFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyFingerprintKeyType(tt.fingerprint, tt.expected)
+			if tt.err == nil && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	t.Run("descriptive mismatch", func(t *testing.T) {
+		err := VerifyFingerprintKeyType("z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p", Ed25519PublicKey)
+		if expected := "expected ed25519-pub, got x25519-pub"; err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain %q, got %v", expected, err)
+		}
+	})
+}
+
+func TestFingerprintKeyType(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	for _, keyType := range supportedKeyTypes {
+		t.Run(keyType.String(), func(t *testing.T) {
+			didKey, err := Encode(keyType, randomValidKey(rng, keyType))
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+
+			got, err := FingerprintKeyType(didKey[len(DIDKeyPrefix):])
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != keyType {
+				t.Errorf("Expected %s, got %s", keyType, got)
+			}
+		})
+	}
+
+	t.Run("key bytes are not validated", func(t *testing.T) {
+		// A truncated Ed25519 key and a P-256 key that is not on the curve
+		for keyType, keyBytes := range map[KeyType][]byte{
+			Ed25519PublicKey: {1, 2, 3},
+			P256PublicKey:    append([]byte{0x02}, bytes.Repeat([]byte{0xff}, 32)...),
+		} {
+			fingerprint := "z" + base58.Encode(encodeMulticodec(keyType, keyBytes))
+			if got, err := FingerprintKeyType(fingerprint); err != nil || got != keyType {
+				t.Errorf("Expected %s, got %s, %v", keyType, got, err)
+			}
+		}
+	})
+
+	tests := []struct {
+		name        string
+		fingerprint string
+		expectedErr error
+	}{
+		{name: "empty", fingerprint: "", expectedErr: ErrEmptyMultibaseString},
+		{name: "unsupported key type", fingerprint: "z" + base58.Encode(encodeMulticodec(0x1337, make([]byte, 32))), expectedErr: ErrUnsupportedKeyType},
+		{name: "not base58-btc", fingerprint: "u7QE", expectedErr: ErrExpectedBase58BTC},
+		{name: "invalid base58", fingerprint: "z6Mk0", expectedErr: ErrInvalidBase58Character},
+		{name: "too long", fingerprint: "z" + strings.Repeat("1", MaxFingerprintChars), expectedErr: ErrFingerprintTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FingerprintKeyType(tt.fingerprint); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -3,17 +3,21 @@ module github.com/dvjn/did-key-go
 go 1.24.3
 
 require (
+	github.com/consensys/gnark-crypto v0.19.0
+	github.com/mr-tron/base58 v1.1.0
 	github.com/multiformats/go-multibase v0.2.0
 	github.com/multiformats/go-multicodec v0.9.0
 	github.com/multiformats/go-varint v0.0.7
+	golang.org/x/crypto v0.35.0
 )
 
 require (
-	github.com/mr-tron/base58 v1.1.0 // indirect
+	github.com/bits-and-blooms/bitset v1.20.0 // indirect
 	github.com/multiformats/go-base32 v0.0.3 // indirect
 	github.com/multiformats/go-base36 v0.1.0 // indirect
 	github.com/yuin/goldmark v1.4.13 // indirect
 	golang.org/x/mod v0.24.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/tools v0.33.0 // indirect
 )
 
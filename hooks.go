@@ -0,0 +1,67 @@
+package didkey
+
+import (
+	"time"
+)
+
+// DecodeHooks are optional callbacks invoked by DecodeWithHooks after each
+// decoding stage, with the time the stage took. A stage that fails still
+// reports its duration before DecodeWithHooks returns the error; later stages
+// are not run. Nil callbacks are skipped.
+type DecodeHooks struct {
+	// AfterMultibase runs after the DID key prefix, input checks and base58-btc decoding
+	AfterMultibase func(elapsed time.Duration)
+
+	// AfterVarint runs after reading the multicodec key type
+	AfterVarint func(elapsed time.Duration)
+
+	// AfterValidation runs after validating the key size and, for EC keys, the point
+	AfterValidation func(elapsed time.Duration)
+}
+
+// DecodeWithHooks decodes a DID key like Decode, reporting per-stage timings
+// to the hooks for tracing and profiling. With no hooks set it is Decode, with
+// no timing overhead.
+func DecodeWithHooks(didKey string, hooks DecodeHooks, opts ...Option) (KeyType, []byte, error) {
+	if hooks.AfterMultibase == nil && hooks.AfterVarint == nil && hooks.AfterValidation == nil {
+		return Decode(didKey, opts...)
+	}
+
+	o := applyOptions(opts)
+	keyType, keyBytes, err := decodeWithHooks(didKey, hooks, o)
+	o.observeDecodeError(err)
+	return keyType, keyBytes, err
+}
+
+func decodeWithHooks(didKey string, hooks DecodeHooks, o options) (KeyType, []byte, error) {
+	start := time.Now()
+	multicodecBytes, err := decodeDIDKey(didKey)
+	start = reportStage(hooks.AfterMultibase, start)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	keyType, keyBytes, err := splitMulticodec(multicodecBytes)
+	start = reportStage(hooks.AfterVarint, start)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = validateKey(keyType, keyBytes, o)
+	reportStage(hooks.AfterValidation, start)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return keyType, keyBytes, nil
+}
+
+// reportStage calls hook, if set, with the time elapsed since start and returns
+// the start time of the next stage
+func reportStage(hook func(time.Duration), start time.Time) time.Time {
+	now := time.Now()
+	if hook != nil {
+		hook(now.Sub(start))
+	}
+	return now
+}
@@ -0,0 +1,77 @@
+package didkey
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDecodeWithHooks(t *testing.T) {
+	var stages []string
+	record := func(stage string) func(time.Duration) {
+		return func(elapsed time.Duration) {
+			if elapsed < 0 {
+				t.Errorf("Negative duration %v for %s", elapsed, stage)
+			}
+			stages = append(stages, stage)
+		}
+	}
+	hooks := DecodeHooks{
+		AfterMultibase:  record("multibase"),
+		AfterVarint:     record("varint"),
+		AfterValidation: record("validation"),
+	}
+
+	t.Run("all stages in order", func(t *testing.T) {
+		stages = nil
+		tv := testVectors["P-256-test"]
+
+		keyType, keyBytes, err := DecodeWithHooks(tv.didKey, hooks)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if keyType != tv.keyType || !bytes.Equal(keyBytes, mustDecodeHex(tv.keyHex)) {
+			t.Errorf("Unexpected result %s, %x", keyType, keyBytes)
+		}
+
+		if expected := []string{"multibase", "varint", "validation"}; !slices.Equal(stages, expected) {
+			t.Errorf("Expected stages %v, got %v", expected, stages)
+		}
+	})
+
+	t.Run("failing stage", func(t *testing.T) {
+		stages = nil
+
+		_, _, err := DecodeWithHooks("did:key:z6Mk", hooks)
+		if !errors.Is(err, ErrInvalidFingerprintLength) {
+			t.Fatalf("Expected ErrInvalidFingerprintLength, got %v", err)
+		}
+
+		if expected := []string{"multibase"}; !slices.Equal(stages, expected) {
+			t.Errorf("Expected stages %v, got %v", expected, stages)
+		}
+	})
+
+	t.Run("partial hooks", func(t *testing.T) {
+		stages = nil
+
+		if _, _, err := DecodeWithHooks(testVectors["Ed25519-from-spec"].didKey, DecodeHooks{AfterVarint: record("varint")}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if expected := []string{"varint"}; !slices.Equal(stages, expected) {
+			t.Errorf("Expected stages %v, got %v", expected, stages)
+		}
+	})
+
+	t.Run("no hooks", func(t *testing.T) {
+		tv := testVectors["Ed25519-from-spec"]
+		keyType, _, err := DecodeWithHooks(tv.didKey, DecodeHooks{})
+		if err != nil || keyType != tv.keyType {
+			t.Errorf("Expected %s, got %s, %v", tv.keyType, keyType, err)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+//go:build interop
+
+// Interop tests comparing ResolveDocument with expected DID Documents. They are
+// opt-in: run them with
+//
+//	go test -tags interop ./...
+//
+// The fixtures vendored in testdata/interop are hand-computed expectations,
+// derived independently of this library but not produced by another
+// implementation, so they cannot catch divergences from one. To check against
+// another implementation, point the DIDKEY_INTEROP_FIXTURES environment
+// variable at a directory of its documents, e.g. produced with
+// `didkit did-resolve`. Each fixture is a JSON object:
+//
+//	{
+//	  "source": "where the document comes from",
+//	  "did": "did:key:z6Mk...",
+//	  "verificationMethodType": "Multikey",
+//	  "document": { ... }
+//	}
+//
+// verificationMethodType is the representation the fixture uses; the
+// resolved document is converted to it before comparing.
+
+package didkey
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type interopFixture struct {
+	Source                 string          `json:"source"`
+	DID                    string          `json:"did"`
+	VerificationMethodType string          `json:"verificationMethodType"`
+	Document               json.RawMessage `json:"document"`
+}
+
+func TestInteropFixtures(t *testing.T) {
+	dir := os.Getenv("DIDKEY_INTEROP_FIXTURES")
+	if dir == "" {
+		dir = filepath.Join("testdata", "interop")
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf("Glob failed: %v", err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("No fixtures in %s", dir)
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile failed: %v", err)
+			}
+
+			var fixture interopFixture
+			if err := json.Unmarshal(data, &fixture); err != nil {
+				t.Fatalf("Invalid fixture: %v", err)
+			}
+
+			doc, err := ResolveDocument(fixture.DID)
+			if err != nil {
+				t.Fatalf("ResolveDocument failed: %v", err)
+			}
+			if fixture.VerificationMethodType != "" {
+				if err := doc.ConvertVerificationMethods(fixture.VerificationMethodType); err != nil {
+					t.Fatalf("ConvertVerificationMethods failed: %v", err)
+				}
+			}
+
+			got, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			if diff, err := compareDocuments(got, fixture.Document); err != nil {
+				t.Fatalf("Invalid document: %v", err)
+			} else if diff != "" {
+				t.Errorf("Document differs from %s reference: %s", fixture.Source, diff)
+			}
+		})
+	}
+}
+
+func TestCompareDocuments(t *testing.T) {
+	const did = "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"
+	const method = `{"id":"` + did + `#z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p","type":"Multikey","controller":"` + did + `","publicKeyMultibase":"z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"}`
+	referenced := `{"id":"` + did + `","verificationMethod":[` + method + `],"keyAgreement":["` + did + `#z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"]}`
+
+	tests := []struct {
+		name  string
+		other string
+		equal bool
+	}{
+		{name: "identical", other: referenced, equal: true},
+		{name: "embedded method", other: `{"keyAgreement":[` + method + `],"id":"` + did + `"}`, equal: true},
+		{name: "relative reference", other: `{"id":"` + did + `","verificationMethod":[` + method + `],"keyAgreement":["#z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"]}`, equal: true},
+		{name: "missing relationship", other: `{"id":"` + did + `","verificationMethod":[` + method + `]}`},
+		{name: "extra member", other: `{"id":"` + did + `","verificationMethod":[` + method + `],"keyAgreement":["#z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"],"alsoKnownAs":[]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := compareDocuments([]byte(referenced), []byte(tt.other))
+			if err != nil {
+				t.Fatalf("compareDocuments failed: %v", err)
+			}
+			if (diff == "") != tt.equal {
+				t.Errorf("Expected equal = %v, got diff %q", tt.equal, diff)
+			}
+		})
+	}
+}
+
+// normalizedDocument is a DID Document in a form where equivalent documents
+// are deeply equal: member order is irrelevant, embedded verification methods
+// are moved to the verification methods, relative ids are made absolute, and
+// verification relationships are sorted
+type normalizedDocument struct {
+	members       map[string]any
+	methods       map[string]map[string]any
+	relationships map[string][]string
+}
+
+// compareDocuments returns a description of the first difference between two
+// JSON DID Documents, or "" if they are equivalent
+func compareDocuments(a, b []byte) (string, error) {
+	na, err := normalizeDocument(a)
+	if err != nil {
+		return "", err
+	}
+	nb, err := normalizeDocument(b)
+	if err != nil {
+		return "", err
+	}
+
+	if !reflect.DeepEqual(na.members, nb.members) {
+		return fmt.Sprintf("members %v != %v", na.members, nb.members), nil
+	}
+	if !reflect.DeepEqual(na.methods, nb.methods) {
+		return fmt.Sprintf("verification methods %v != %v", na.methods, nb.methods), nil
+	}
+	if !reflect.DeepEqual(na.relationships, nb.relationships) {
+		return fmt.Sprintf("verification relationships %v != %v", na.relationships, nb.relationships), nil
+	}
+	return "", nil
+}
+
+func normalizeDocument(data []byte) (*normalizedDocument, error) {
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	id, _ := members["id"].(string)
+	absolute := func(ref string) string {
+		if len(ref) > 0 && ref[0] == '#' {
+			return id + ref
+		}
+		return ref
+	}
+
+	doc := &normalizedDocument{
+		members:       members,
+		methods:       make(map[string]map[string]any),
+		relationships: make(map[string][]string),
+	}
+	addMethod := func(value any) (string, error) {
+		method, ok := value.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("verification method %v is not an object", value)
+		}
+		methodID, _ := method["id"].(string)
+		methodID = absolute(methodID)
+		method["id"] = methodID
+		doc.methods[methodID] = method
+		return methodID, nil
+	}
+
+	if methods, ok := members["verificationMethod"].([]any); ok {
+		for _, method := range methods {
+			if _, err := addMethod(method); err != nil {
+				return nil, err
+			}
+		}
+	}
+	delete(members, "verificationMethod")
+
+	for _, relationship := range []string{Authentication, AssertionMethod, CapabilityDelegation, CapabilityInvocation, KeyAgreement} {
+		entries, ok := members[relationship].([]any)
+		if !ok {
+			continue
+		}
+		delete(members, relationship)
+
+		refs := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			ref, ok := entry.(string)
+			if !ok {
+				var err error
+				if ref, err = addMethod(entry); err != nil {
+					return nil, err
+				}
+			}
+			refs = append(refs, absolute(ref))
+		}
+		slices.Sort(refs)
+		doc.relationships[relationship] = refs
+	}
+
+	return doc, nil
+}
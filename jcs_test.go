@@ -0,0 +1,61 @@
+package didkey
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "whitespace and key order", input: `{ "b": 1, "a": [true, null, "x"] }`, expected: `{"a":[true,null,"x"],"b":1}`},
+		{name: "nested objects", input: `{"z":{"d":2,"c":1},"y":{}}`, expected: `{"y":{},"z":{"c":1,"d":2}}`},
+		// RFC 8785 section 3.2.3: sorted by UTF-16 code units, so U+1F600
+		// (surrogates 0xD83D 0xDE00) sorts before U+FB33
+		{name: "UTF-16 key order", input: `{"\ufb33":1,"\ud83d\ude00":2,"\u00e9":3}`, expected: "{\"\u00e9\":3,\"\U0001F600\":2,\"\uFB33\":1}"},
+		{name: "string escaping", input: `"<&é\n\u001f\"\\/"`, expected: `"<&é\n\u001f\"\\/"`},
+		{name: "integers", input: `[0, -0, 1, -1, 100, 1e2, 9007199254740991]`, expected: `[0,0,1,-1,100,100,9007199254740991]`},
+		{name: "fractions", input: `[1.5, 0.1, -0.000001, 0.0000001]`, expected: `[1.5,0.1,-0.000001,1e-7]`},
+		{name: "large numbers", input: `[1e21, 1e20, 123456789012345678901234, 4.5e300]`, expected: `[1e+21,100000000000000000000,1.2345678901234569e+23,4.5e+300]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			canonical, err := canonicalizeJSON([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("canonicalizeJSON failed: %v", err)
+			}
+			if string(canonical) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, canonical)
+			}
+		})
+	}
+
+	for _, input := range []string{`{`, `1e400`, `{} {}`} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			if _, err := canonicalizeJSON([]byte(input)); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+
+	t.Run("number formatting", func(t *testing.T) {
+		// Extremes, precision and the fixed/exponent notation boundaries
+		for input, expected := range map[string]string{
+			"5e-324":                 "5e-324",
+			"1.7976931348623157e308": "1.7976931348623157e+308",
+			"333333333.3333333":      "333333333.3333333",
+			"1e23":                   "1e+23",
+			"295147905179352830000":  "295147905179352830000",
+			"0.000001":               "0.000001",
+			"-1.0000000000000001e-7": "-1.0000000000000001e-7",
+		} {
+			if got, err := canonicalNumber(json.Number(input)); err != nil || got != expected {
+				t.Errorf("canonicalNumber(%s): expected %s, got %s, %v", input, expected, got, err)
+			}
+		}
+	})
+}
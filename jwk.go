@@ -0,0 +1,132 @@
+package didkey
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"math/big"
+)
+
+// JWK is the public JSON Web Key (RFC 7517) representation of a DID key
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWK returns the public JSON Web Key for the DID key. Ed25519 and X25519 keys
+// map to OKP keys (RFC 8037); secp256k1, P-256 and P-384 keys map to EC keys
+// with both coordinates. BLS12-381 keys have no JWK representation.
+func (dk *DIDKey) JWK() (*JWK, error) {
+	switch dk.keyType {
+	case Ed25519PublicKey:
+		return &JWK{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(dk.keyBytes)}, nil
+	case X25519PublicKey:
+		return &JWK{Kty: "OKP", Crv: "X25519", X: base64.RawURLEncoding.EncodeToString(dk.keyBytes)}, nil
+	}
+
+	curve, ok := curveFor(dk.keyType)
+	if !ok {
+		return nil, ErrJWKUnsupportedWithContext(dk.keyType)
+	}
+
+	x, y, ok := curve.decompress(dk.keyBytes)
+	if !ok {
+		return nil, ErrInvalidPointWithContext(dk.keyType)
+	}
+
+	return &JWK{
+		Kty: "EC",
+		Crv: curve.name,
+		X:   base64.RawURLEncoding.EncodeToString(x.FillBytes(make([]byte, curve.size))),
+		Y:   base64.RawURLEncoding.EncodeToString(y.FillBytes(make([]byte, curve.size))),
+	}, nil
+}
+
+// JWKThumbprint returns the base64url-encoded SHA-256 JWK thumbprint (RFC 7638)
+// of the DID key, as commonly used for JOSE "kid" values
+func (dk *DIDKey) JWKThumbprint() (string, error) {
+	jwk, err := dk.JWK()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(jwk.thumbprintInput())
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// thumbprintInput returns the canonical JSON of the required JWK members:
+// lexicographically ordered with no whitespace. All values are base64url or
+// plain ASCII, so no escaping is needed.
+func (j *JWK) thumbprintInput() []byte {
+	if j.Kty == "EC" {
+		return []byte(`{"crv":"` + j.Crv + `","kty":"` + j.Kty + `","x":"` + j.X + `","y":"` + j.Y + `"}`)
+	}
+	return []byte(`{"crv":"` + j.Crv + `","kty":"` + j.Kty + `","x":"` + j.X + `"}`)
+}
+
+// EncodeFromJWKCoordinates encodes a public key given by the base64url "x" and
+// "y" members of a JWK, skipping the assembly of key bytes. For Ed25519 and
+// X25519 (OKP) keys only xB64 is used; for EC keys the point is validated and
+// compressed as by EncodeECCoordinates.
+func EncodeFromJWKCoordinates(keyType KeyType, xB64, yB64 string) (string, error) {
+	x, err := base64.RawURLEncoding.DecodeString(xB64)
+	if err != nil {
+		return "", ErrInvalidJWKWithContext("x: " + err.Error())
+	}
+
+	switch keyType {
+	case Ed25519PublicKey, X25519PublicKey:
+		return Encode(keyType, x)
+	}
+
+	if _, ok := curveFor(keyType); !ok {
+		return "", ErrJWKUnsupportedWithContext(keyType)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(yB64)
+	if err != nil {
+		return "", ErrInvalidJWKWithContext("y: " + err.Error())
+	}
+
+	return EncodeECCoordinates(keyType, x, y)
+}
+
+// publicKey decodes the DID key of a public JWK, the inverse of (*DIDKey).JWK
+func (j *JWK) publicKey(opts ...Option) (*DIDKey, error) {
+	x, err := base64.RawURLEncoding.DecodeString(j.X)
+	if err != nil {
+		return nil, ErrInvalidJWKWithContext("x: " + err.Error())
+	}
+
+	switch {
+	case j.Kty == "OKP" && j.Crv == "Ed25519":
+		return FromBytes(Ed25519PublicKey, x, opts...)
+	case j.Kty == "OKP" && j.Crv == "X25519":
+		return FromBytes(X25519PublicKey, x, opts...)
+	case j.Kty != "EC":
+		return nil, ErrInvalidJWKWithContext("unsupported key " + j.Kty + "/" + j.Crv)
+	}
+
+	keyType, curve, ok := curveByName(j.Crv)
+	if !ok {
+		return nil, ErrInvalidJWKWithContext("unsupported curve " + j.Crv)
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(j.Y)
+	if err != nil {
+		return nil, ErrInvalidJWKWithContext("y: " + err.Error())
+	}
+
+	if len(x) != curve.size || len(y) != curve.size {
+		return nil, ErrInvalidCoordinateSizeWithContext(keyType, curve.size, len(x), len(y))
+	}
+
+	xInt := new(big.Int).SetBytes(x)
+	yInt := new(big.Int).SetBytes(y)
+	if !curve.isOnCurve(xInt, yInt) {
+		return nil, ErrInvalidPointWithContext(keyType)
+	}
+
+	return FromBytes(keyType, curve.compress(xInt, yInt), opts...)
+}
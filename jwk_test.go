@@ -0,0 +1,134 @@
+package didkey
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestJWK(t *testing.T) {
+	t.Run("Ed25519", func(t *testing.T) {
+		dk, err := Parse(testVectors["Ed25519-test-1"].didKey)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		jwk, err := dk.JWK()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// RFC 8037 Appendix A.2
+		expected := JWK{Kty: "OKP", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}
+		if *jwk != expected {
+			t.Errorf("Expected %+v, got %+v", expected, *jwk)
+		}
+	})
+
+	t.Run("P-256", func(t *testing.T) {
+		dk, err := Parse(testVectors["P-256-test"].didKey)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		jwk, err := dk.JWK()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if jwk.Kty != "EC" || jwk.Crv != "P-256" {
+			t.Errorf("Unexpected kty/crv %s/%s", jwk.Kty, jwk.Crv)
+		}
+
+		x, _ := base64.RawURLEncoding.DecodeString(jwk.X)
+		y, _ := base64.RawURLEncoding.DecodeString(jwk.Y)
+		didKey, err := EncodeECCoordinates(P256PublicKey, x, y)
+		if err != nil {
+			t.Fatalf("EncodeECCoordinates failed: %v", err)
+		}
+
+		if didKey != dk.String() {
+			t.Errorf("Expected JWK coordinates to round-trip to %s, got %s", dk.String(), didKey)
+		}
+	})
+
+	t.Run("BLS12-381 unsupported", func(t *testing.T) {
+		dk, err := FromBytes(Bls12381G1PublicKey, blsPlaceholderKey(48))
+		if err != nil {
+			t.Fatalf("FromBytes failed: %v", err)
+		}
+
+		if _, err := dk.JWK(); !errors.Is(err, ErrJWKUnsupported) {
+			t.Errorf("Expected ErrJWKUnsupported, got %v", err)
+		}
+	})
+}
+
+func TestEncodeFromJWKCoordinates(t *testing.T) {
+	// RFC 7515 Appendix A.3; y is odd, so the compressed prefix is 0x03
+	p256Key := mustDecodeHex("037fcdce2770f6c45d4183cbee6fdb4b7b580733357be9ef13bacf6e3c7bd15445")
+	p256DID, err := Encode(P256PublicKey, p256Key)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	const (
+		p256X = "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU"
+		p256Y = "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"
+	)
+
+	tests := []struct {
+		name        string
+		keyType     KeyType
+		x, y        string
+		expected    string
+		expectedErr error
+	}{
+		// RFC 8037 Appendix A.2
+		{name: "Ed25519", keyType: Ed25519PublicKey, x: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo", expected: testVectors["Ed25519-test-1"].didKey},
+		{name: "Ed25519 ignores y", keyType: Ed25519PublicKey, x: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo", y: "!", expected: testVectors["Ed25519-test-1"].didKey},
+		{name: "P-256", keyType: P256PublicKey, x: p256X, y: p256Y, expected: p256DID},
+		{name: "invalid x", keyType: Ed25519PublicKey, x: "11qY+", expectedErr: ErrInvalidJWK},
+		{name: "invalid y", keyType: P256PublicKey, x: p256X, y: "x_FE/", expectedErr: ErrInvalidJWK},
+		{name: "short y", keyType: P256PublicKey, x: p256X, y: p256Y[:40], expectedErr: ErrInvalidCoordinateSize},
+		{name: "off curve", keyType: P256PublicKey, x: p256X, y: p256X, expectedErr: ErrInvalidPoint},
+		{name: "short OKP key", keyType: X25519PublicKey, x: "AAAA", expectedErr: ErrInvalidKeySize},
+		{name: "BLS12-381", keyType: Bls12381G1PublicKey, x: p256X, expectedErr: ErrJWKUnsupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			didKey, err := EncodeFromJWKCoordinates(tt.keyType, tt.x, tt.y)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if didKey != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, didKey)
+			}
+		})
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	dk, err := Parse(testVectors["Ed25519-test-1"].didKey)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	thumbprint, err := dk.JWKThumbprint()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// RFC 8037 Appendix A.3
+	expected := "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+	if thumbprint != expected {
+		t.Errorf("Expected %s, got %s", expected, thumbprint)
+	}
+}
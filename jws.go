@@ -0,0 +1,85 @@
+package didkey
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// jwsAlgorithms maps each verifiable key type to its JWS "alg" (RFC 7518, RFC 8037, RFC 8812)
+var jwsAlgorithms = map[KeyType]string{
+	Ed25519PublicKey:   "EdDSA",
+	Secp256k1PublicKey: "ES256K",
+	P256PublicKey:      "ES256",
+	P384PublicKey:      "ES384",
+}
+
+// jwsHeader is the part of the JWS protected header checked by VerifyJWS
+type jwsHeader struct {
+	Alg  string   `json:"alg"`
+	Crit []string `json:"crit"`
+}
+
+// VerifyJWS verifies a JWS compact serialization signed by the DID key and
+// returns its decoded payload. The header "alg" must match the key type:
+// EdDSA for Ed25519, ES256K for secp256k1, ES256 for P-256 and ES384 for
+// P-384. Headers with critical extensions ("crit") are rejected, as none are
+// understood. Key agreement keys (X25519) return ErrKeyAgreementKeyNotSignable.
+func VerifyJWS(compactJWS string, didKey string, opts ...Option) ([]byte, error) {
+	dk, err := Parse(didKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireSignatureKey(dk.keyType); err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(compactJWS, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidJWSWithContext("expected 3 dot-separated parts")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidJWSWithContext("header is not base64url")
+	}
+
+	var header jwsHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, ErrInvalidJWSWithContext("header is not a JSON object")
+	}
+
+	if header.Crit != nil {
+		return nil, ErrInvalidJWSWithContext("unsupported critical header parameters")
+	}
+
+	alg, ok := jwsAlgorithms[dk.keyType]
+	if !ok {
+		return nil, ErrVerificationUnsupportedWithContext(dk.keyType)
+	}
+	if header.Alg != alg {
+		return nil, ErrAlgorithmMismatchWithContext(header.Alg, dk.keyType)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidJWSWithContext("payload is not base64url")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidJWSWithContext("signature is not base64url")
+	}
+
+	// The algorithm fixes the hash, whatever WithHash says
+	valid, err := dk.Verify([]byte(parts[0]+"."+parts[1]), signature, append(slices.Clip(opts), WithHash(0))...)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidSignature
+	}
+
+	return payload, nil
+}
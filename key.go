@@ -0,0 +1,132 @@
+package didkey
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+)
+
+// zeroDIDKeyString is printed for a nil or zero-value DIDKey, which has no DID
+// key string
+const zeroDIDKeyString = "<zero DIDKey>"
+
+// DIDKey is a decoded DID key: a key type and its raw public key bytes
+type DIDKey struct {
+	keyType  KeyType
+	keyBytes []byte
+}
+
+// FromBytes creates a DIDKey from raw key bytes, validating them as Encode does.
+// The key bytes are copied. As with Encode, uncompressed secp256k1 keys are compressed.
+func FromBytes(keyType KeyType, keyBytes []byte, opts ...Option) (*DIDKey, error) {
+	if len(keyBytes) == 0 {
+		return nil, ErrEmptyKeyBytes
+	}
+
+	o := applyOptions(opts)
+	keyBytes, err := normalizeKeyBytes(keyType, keyBytes, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateKey(keyType, keyBytes, o); err != nil {
+		return nil, err
+	}
+
+	return &DIDKey{keyType: keyType, keyBytes: bytes.Clone(keyBytes)}, nil
+}
+
+// FromHexAutodetect creates a DIDKey from hex-encoded key bytes, inferring the
+// key type from their size. It is meant for quick debugging: only BLS12-381 and
+// P-384 keys have sizes unique to their type, so 32-byte (Ed25519, X25519) and
+// 33-byte (secp256k1, P-256) keys return ErrKeyTypeNotInferred listing the
+// candidates and must be created with FromBytes instead.
+func FromHexAutodetect(hexKey string, opts ...Option) (*DIDKey, error) {
+	keyBytes, err := hex.DecodeString(hexKey)
+	if err != nil {
+		return nil, ErrInvalidHexWithContext(err)
+	}
+	if len(keyBytes) == 0 {
+		return nil, ErrEmptyKeyBytes
+	}
+
+	candidates := keyTypesOfSize(len(keyBytes))
+	if len(candidates) != 1 {
+		return nil, ErrKeyTypeNotInferredWithContext(len(keyBytes), candidates)
+	}
+
+	return FromBytes(candidates[0], keyBytes, opts...)
+}
+
+// Parse decodes a DID key string into a DIDKey
+func Parse(didKey string, opts ...Option) (*DIDKey, error) {
+	keyType, keyBytes, err := Decode(didKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DIDKey{keyType: keyType, keyBytes: keyBytes}, nil
+}
+
+// KeyType returns the key type of the DID key
+func (dk *DIDKey) KeyType() KeyType {
+	return dk.keyType
+}
+
+// Bytes returns a copy of the raw public key bytes
+func (dk *DIDKey) Bytes() []byte {
+	return bytes.Clone(dk.keyBytes)
+}
+
+// String returns the DID key string, e.g. did:key:z6Mk..., or "<zero DIDKey>"
+// for a nil or zero-value DIDKey
+func (dk *DIDKey) String() string {
+	if dk.isZero() {
+		return zeroDIDKeyString
+	}
+	return encodeDIDKey(dk.keyType, dk.keyBytes)
+}
+
+// Format implements fmt.Formatter:
+//   - %s, %v and %q print the DID key string, as String
+//   - %+v prints the key type and fingerprint, e.g. {KeyType:Ed25519 Fingerprint:z6Mk...}
+//   - %x and %X print the raw public key bytes in hex
+func (dk *DIDKey) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		if dk.isZero() {
+			fmt.Fprint(f, zeroDIDKeyString)
+			return
+		}
+		fmt.Fprintf(f, "{KeyType:%s Fingerprint:%s}", KeyTypeName(dk.keyType), dk.Fingerprint())
+	case verb == 's' || verb == 'v' || verb == 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), dk.String())
+	case verb == 'x' || verb == 'X':
+		var keyBytes []byte
+		if dk != nil {
+			keyBytes = dk.keyBytes
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, verb), keyBytes)
+	default:
+		fmt.Fprintf(f, "%%!%c(*didkey.DIDKey=%s)", verb, dk.String())
+	}
+}
+
+func (dk *DIDKey) isZero() bool {
+	return dk == nil || len(dk.keyBytes) == 0
+}
+
+// Fingerprint returns the multibase-encoded method-specific identifier of the
+// DID key, e.g. z6Mk...
+func (dk *DIDKey) Fingerprint() string {
+	return encodeDIDKey(dk.keyType, dk.keyBytes)[len(DIDKeyPrefix):]
+}
+
+// Clone returns a deep copy of the DID key that shares no memory with the original
+func (dk *DIDKey) Clone() *DIDKey {
+	if dk == nil {
+		return nil
+	}
+
+	return &DIDKey{keyType: dk.keyType, keyBytes: bytes.Clone(dk.keyBytes)}
+}
@@ -0,0 +1,177 @@
+package didkey
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDIDKey(t *testing.T) {
+	for name, tv := range testVectors {
+		t.Run(name, func(t *testing.T) {
+			keyBytes := mustDecodeHex(tv.keyHex)
+
+			fromBytes, err := FromBytes(tv.keyType, keyBytes)
+			if err != nil {
+				t.Fatalf("FromBytes failed: %v", err)
+			}
+
+			parsed, err := Parse(tv.didKey)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			for _, dk := range []*DIDKey{fromBytes, parsed} {
+				if dk.KeyType() != tv.keyType {
+					t.Errorf("Expected key type %s, got %s", tv.keyType, dk.KeyType())
+				}
+
+				if !bytes.Equal(dk.Bytes(), keyBytes) {
+					t.Errorf("Expected key bytes %x, got %x", keyBytes, dk.Bytes())
+				}
+
+				if dk.String() != tv.didKey {
+					t.Errorf("Expected %s, got %s", tv.didKey, dk.String())
+				}
+
+				if dk.Fingerprint() != tv.didKey[len(DIDKeyPrefix):] {
+					t.Errorf("Expected fingerprint %s, got %s", tv.didKey[len(DIDKeyPrefix):], dk.Fingerprint())
+				}
+			}
+		})
+	}
+
+	t.Run("FromBytes copies input", func(t *testing.T) {
+		keyBytes := make([]byte, 32)
+		dk, err := FromBytes(Ed25519PublicKey, keyBytes)
+		if err != nil {
+			t.Fatalf("FromBytes failed: %v", err)
+		}
+
+		keyBytes[0] = 0xff
+		if dk.Bytes()[0] != 0 {
+			t.Errorf("Mutating the input changed the DID key")
+		}
+	})
+
+	t.Run("FromBytes validates", func(t *testing.T) {
+		if _, err := FromBytes(Ed25519PublicKey, make([]byte, 31)); err == nil {
+			t.Errorf("Expected error but got none")
+		}
+	})
+}
+
+func TestDIDKeyClone(t *testing.T) {
+	original, err := Parse(testVectors["Ed25519-from-spec"].didKey)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	originalBytes := original.Bytes()
+
+	clone := original.Clone()
+	clone.keyBytes[0] ^= 0xff
+
+	if !bytes.Equal(original.keyBytes, originalBytes) {
+		t.Errorf("Mutating the clone changed the original: %x", original.keyBytes)
+	}
+
+	if clone.String() == original.String() {
+		t.Errorf("Expected the mutated clone to differ from the original")
+	}
+
+	if (*DIDKey)(nil).Clone() != nil {
+		t.Errorf("Expected nil clone of nil DIDKey")
+	}
+}
+
+func TestDIDKeyFormat(t *testing.T) {
+	tv := testVectors["Ed25519-test-1"]
+	dk, err := Parse(tv.didKey)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	fingerprint := tv.didKey[len(DIDKeyPrefix):]
+
+	tests := []struct {
+		format   string
+		value    any
+		expected string
+	}{
+		{format: "%s", value: dk, expected: tv.didKey},
+		{format: "%v", value: dk, expected: tv.didKey},
+		{format: "%q", value: dk, expected: `"` + tv.didKey + `"`},
+		{format: "%+v", value: dk, expected: "{KeyType:Ed25519 Fingerprint:" + fingerprint + "}"},
+		{format: "%x", value: dk, expected: tv.keyHex},
+		{format: "%X", value: dk, expected: strings.ToUpper(tv.keyHex)},
+		{format: "%60s", value: dk, expected: strings.Repeat(" ", 60-len(tv.didKey)) + tv.didKey},
+		{format: "%d", value: dk, expected: "%!d(*didkey.DIDKey=" + tv.didKey + ")"},
+		{format: "%v", value: []*DIDKey{dk}, expected: "[" + tv.didKey + "]"},
+		{format: "%s", value: &DIDKey{}, expected: "<zero DIDKey>"},
+		{format: "%+v", value: (*DIDKey)(nil), expected: "<zero DIDKey>"},
+		{format: "%x", value: (*DIDKey)(nil), expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := fmt.Sprintf(tt.format, tt.value); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	var stringer fmt.Stringer = dk
+	if stringer.String() != tv.didKey {
+		t.Errorf("Expected String() %s, got %s", tv.didKey, stringer.String())
+	}
+}
+
+func TestFromHexAutodetect(t *testing.T) {
+	const blsG1Hex = "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a"
+
+	dk, err := FromHexAutodetect(blsG1Hex)
+	if err != nil {
+		t.Fatalf("FromHexAutodetect failed: %v", err)
+	}
+	if dk.KeyType() != Bls12381G1PublicKey {
+		t.Errorf("Expected %s, got %s", Bls12381G1PublicKey, dk.KeyType())
+	}
+	if got := fmt.Sprintf("%x", dk); got != blsG1Hex {
+		t.Errorf("Expected key %s, got %s", blsG1Hex, got)
+	}
+
+	tests := []struct {
+		name        string
+		hexKey      string
+		expectedErr error
+		expectedMsg string
+	}{
+		{
+			name:        "ambiguous 32 bytes",
+			hexKey:      testVectors["Ed25519-from-spec"].keyHex,
+			expectedErr: ErrKeyTypeNotInferred,
+			expectedMsg: "cannot infer key type from key size: 32-byte keys could be Ed25519 or X25519, specify the key type",
+		},
+		{
+			name:        "unknown size",
+			hexKey:      "0102",
+			expectedErr: ErrKeyTypeNotInferred,
+			expectedMsg: "cannot infer key type from key size: no key type has 2-byte keys",
+		},
+		{name: "invalid hex", hexKey: "zz", expectedErr: ErrInvalidHex},
+		{name: "empty", hexKey: "", expectedErr: ErrEmptyKeyBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromHexAutodetect(tt.hexKey)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedMsg != "" && err.Error() != tt.expectedMsg {
+				t.Errorf("Expected %q, got %q", tt.expectedMsg, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package didkey
+
+// DecodeLegacySecp256k1 decodes a secp256k1 DID key as produced by older did:key
+// implementations, returning the compressed key bytes Decode would return for
+// the canonical DID key.
+//
+// The multicodec table has only ever assigned 0xe7 (secp256k1-pub) to secp256k1
+// public keys, so it is the only code accepted; 0x1301 (secp256k1-priv) is a
+// private key code, not an alternate, and fails with ErrKeyTypeMismatch like
+// every other key type. What older producers varied is the key encoding under
+// 0xe7: besides the 33-byte compressed form, the 65-byte uncompressed (0x04)
+// and hybrid (0x06, 0x07) forms are accepted.
+func DecodeLegacySecp256k1(didKey string, opts ...Option) ([]byte, error) {
+	o := applyOptions(opts)
+	keyBytes, err := decodeLegacySecp256k1(didKey, o)
+	o.observeDecodeError(err)
+	return keyBytes, err
+}
+
+func decodeLegacySecp256k1(didKey string, o options) ([]byte, error) {
+	multicodecBytes, err := decodeDIDKey(didKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyType, keyBytes, err := splitMulticodec(multicodecBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyType != Secp256k1PublicKey {
+		return nil, ErrKeyTypeMismatchWithContext(Secp256k1PublicKey, keyType)
+	}
+
+	keyBytes, err = normalizeKeyBytes(keyType, keyBytes, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateKey(keyType, keyBytes, o); err != nil {
+		return nil, err
+	}
+
+	return keyBytes, nil
+}
+
+// CanonicalSecp256k1 re-encodes a secp256k1 DID key accepted by
+// DecodeLegacySecp256k1 in the canonical form produced by Encode: code 0xe7
+// followed by the compressed key. Canonical DID keys are returned unchanged.
+func CanonicalSecp256k1(didKey string, opts ...Option) (string, error) {
+	keyBytes, err := DecodeLegacySecp256k1(didKey, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	return encodeDIDKey(Secp256k1PublicKey, keyBytes), nil
+}
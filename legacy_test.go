@@ -0,0 +1,73 @@
+package didkey
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDecodeLegacySecp256k1(t *testing.T) {
+	// The secp256k1 generator in compressed, uncompressed and hybrid form
+	const (
+		x = "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+		y = "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+	)
+	compressed := mustDecodeHex("02" + x)
+	canonical, err := Encode(Secp256k1PublicKey, compressed)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	withCode := func(keyType KeyType, keyHex string) string {
+		return DIDKeyPrefix + "z" + encodeBase58BTC(encodeMulticodec(keyType, mustDecodeHex(keyHex)))
+	}
+
+	tests := []struct {
+		name        string
+		didKey      string
+		expectedErr error
+	}{
+		{name: "canonical", didKey: canonical},
+		{name: "uncompressed", didKey: withCode(Secp256k1PublicKey, "04"+x+y)},
+		{name: "hybrid", didKey: withCode(Secp256k1PublicKey, "06"+x+y)},
+		{name: "uncompressed off curve", didKey: withCode(Secp256k1PublicKey, "04"+x+x), expectedErr: ErrInvalidPoint},
+		{name: "private key code", didKey: withCode(KeyType(0x1301), "02"+x), expectedErr: ErrKeyTypeMismatch},
+		{name: "Ed25519", didKey: testVectors["Ed25519-from-spec"].didKey, expectedErr: ErrKeyTypeMismatch},
+		{name: "invalid DID key", didKey: "did:web:example.com", expectedErr: ErrInvalidDIDKeyPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyBytes, err := DecodeLegacySecp256k1(tt.didKey)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				if _, err := CanonicalSecp256k1(tt.didKey); !errors.Is(err, tt.expectedErr) {
+					t.Errorf("CanonicalSecp256k1: expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !bytes.Equal(keyBytes, compressed) {
+				t.Errorf("Expected key bytes %x, got %x", compressed, keyBytes)
+			}
+
+			didKey, err := CanonicalSecp256k1(tt.didKey)
+			if err != nil {
+				t.Fatalf("CanonicalSecp256k1 failed: %v", err)
+			}
+			if didKey != canonical {
+				t.Errorf("Expected %s, got %s", canonical, didKey)
+			}
+		})
+	}
+
+	t.Run("Decode rejects legacy forms", func(t *testing.T) {
+		if _, _, err := Decode(withCode(Secp256k1PublicKey, "04"+x+y)); !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected ErrInvalidKeySize, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package didkey
+
+import (
+	"slices"
+)
+
+// isSigningKeyType reports whether keys of the type can produce signatures,
+// i.e. whether the spec assigns them the assertionMethod relationship
+func isSigningKeyType(keyType KeyType) bool {
+	return slices.Contains(VerificationRelationships(keyType), AssertionMethod)
+}
+
+// requireSignatureKey is the guard of every API that signs or verifies
+// signatures, so that they all reject key agreement keys (X25519) with
+// ErrKeyAgreementKeyNotSignable, unsupported key types, including that of a
+// zero-value DIDKey, with ErrUnsupportedKeyType, and other key types that
+// cannot sign with ErrVerificationUnsupported
+func requireSignatureKey(keyType KeyType) error {
+	if isSigningKeyType(keyType) {
+		return nil
+	}
+
+	relationships := VerificationRelationships(keyType)
+	switch {
+	case relationships == nil:
+		return ErrUnsupportedKeyTypeWithContext(keyType)
+	case slices.Contains(relationships, KeyAgreement):
+		return ErrKeyAgreementKeyNotSignableWithContext(keyType)
+	default:
+		return ErrVerificationUnsupportedWithContext(keyType)
+	}
+}
+
+// SigningMethodURI returns the verification method URI identifying the DID key
+// in signatures, did:key:<fingerprint>#<fingerprint>, as used for the
+// verificationMethod of JSON-LD proofs. Key agreement keys (X25519) cannot
+// sign and return ErrKeyAgreementKeyNotSignable.
+func (dk *DIDKey) SigningMethodURI() (string, error) {
+	if err := requireSignatureKey(dk.keyType); err != nil {
+		return "", err
+	}
+
+	return dk.String() + "#" + dk.Fingerprint(), nil
+}
+
+// SplitSigningMethodURI splits a signing verification method URI into its DID
+// and fragment, validating that the fragment is the fingerprint of the DID key
+// as required by did:key
+func SplitSigningMethodURI(uri string, opts ...Option) (did string, fragment string, err error) {
+	u, keyType, _, err := parseDIDURL(uri, opts...)
+	if err != nil {
+		return "", "", err
+	}
+
+	if u.Path != "" || u.Query != "" || u.Fragment == "" {
+		return "", "", ErrInvalidVerificationMethodURIWithContext(uri)
+	}
+
+	fingerprint := u.DID[len(DIDKeyPrefix):]
+	if u.Fragment != fingerprint {
+		return "", "", ErrFragmentMismatchWithContext(fingerprint, u.Fragment)
+	}
+
+	if err := requireSignatureKey(keyType); err != nil {
+		return "", "", err
+	}
+
+	return u.DID, u.Fragment, nil
+}
@@ -0,0 +1,183 @@
+package didkey
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+func TestSigningMethodURI(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	uri := did + "#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	dk, err := Parse(did)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	got, err := dk.SigningMethodURI()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got != uri {
+		t.Errorf("Expected %s, got %s", uri, got)
+	}
+
+	splitDID, fragment, err := SplitSigningMethodURI(got)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if splitDID != did || fragment != did[len(DIDKeyPrefix):] {
+		t.Errorf("Unexpected split %s, %s", splitDID, fragment)
+	}
+
+	// The signing method URI is the id of the primary verification method
+	doc, err := ResolveDocument(did)
+	if err != nil {
+		t.Fatalf("ResolveDocument failed: %v", err)
+	}
+
+	if doc.AssertionMethod[0] != uri {
+		t.Errorf("Expected assertionMethod %s, got %s", uri, doc.AssertionMethod[0])
+	}
+}
+
+func TestSigningMethodURIX25519(t *testing.T) {
+	dk, err := Parse("did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if _, err := dk.SigningMethodURI(); !errors.Is(err, ErrNotSigningKey) {
+		t.Errorf("Expected ErrNotSigningKey, got %v", err)
+	}
+
+	_, _, err = SplitSigningMethodURI("did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p#z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p")
+	if !errors.Is(err, ErrNotSigningKey) {
+		t.Errorf("Expected ErrNotSigningKey, got %v", err)
+	}
+}
+
+func TestRequireSignatureKey(t *testing.T) {
+	did := "did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"
+	fingerprint := did[len(DIDKeyPrefix):]
+
+	dk, err := Parse(did)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	x25519Key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	apis := map[string]func() error{
+		"Verify": func() error {
+			_, err := dk.Verify([]byte("message"), make([]byte, 64))
+			return err
+		},
+		"VerifyBLS": func() error {
+			_, err := dk.VerifyBLS([]byte("message"), make([]byte, 96))
+			return err
+		},
+		"AsVerifier": func() error {
+			_, err := dk.AsVerifier()
+			return err
+		},
+		"VerifyJWS": func() error {
+			_, err := VerifyJWS("eyJhbGciOiJFZERTQSJ9.cGF5bG9hZA.c2lnbmF0dXJl", did)
+			return err
+		},
+		"VerifyCredentialProof": func() error {
+			proof := Proof{
+				Type:               dataIntegrityProofType,
+				Cryptosuite:        EdDSACryptosuite,
+				VerificationMethod: did + "#" + fingerprint,
+			}
+			_, err := VerifyCredentialProof([]byte(`{"issuer":"`+did+`"}`), proof)
+			return err
+		},
+		"Sign": func() error {
+			_, _, err := Sign(crypto.PrivateKey(x25519Key), []byte("message"))
+			return err
+		},
+		"SigningMethodURI": func() error {
+			_, err := dk.SigningMethodURI()
+			return err
+		},
+		"SplitSigningMethodURI": func() error {
+			_, _, err := SplitSigningMethodURI(did + "#" + fingerprint)
+			return err
+		},
+	}
+
+	for name, api := range apis {
+		t.Run(name, func(t *testing.T) {
+			err := api()
+			if !errors.Is(err, ErrKeyAgreementKeyNotSignable) {
+				t.Fatalf("Expected ErrKeyAgreementKeyNotSignable, got %v", err)
+			}
+			if err.Error() != ErrKeyAgreementKeyNotSignableWithContext(X25519PublicKey).Error() {
+				t.Errorf("Expected the same error for every API, got %v", err)
+			}
+		})
+	}
+
+	t.Run("zero-value DIDKey", func(t *testing.T) {
+		var zero DIDKey
+		zeroAPIs := map[string]func() error{
+			"Verify": func() error {
+				_, err := zero.Verify([]byte("message"), make([]byte, 64))
+				return err
+			},
+			"VerifyBLS": func() error {
+				_, err := zero.VerifyBLS([]byte("message"), make([]byte, 96))
+				return err
+			},
+			"AsVerifier": func() error {
+				_, err := zero.AsVerifier()
+				return err
+			},
+			"SigningMethodURI": func() error {
+				_, err := zero.SigningMethodURI()
+				return err
+			},
+		}
+
+		for name, api := range zeroAPIs {
+			err := api()
+			if !errors.Is(err, ErrUnsupportedKeyType) || errors.Is(err, ErrKeyAgreementKeyNotSignable) {
+				t.Errorf("%s: expected ErrUnsupportedKeyType, got %v", name, err)
+			}
+		}
+	})
+}
+
+func TestSplitSigningMethodURIErrors(t *testing.T) {
+	did := "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+
+	tests := []struct {
+		name string
+		uri  string
+		err  error
+	}{
+		{"fragment mismatch", did + "#z6MktwupdmLXVVqTzCw4i46r4uGyosGXRnR3XjN4Zq7oMMsw", ErrFragmentMismatch},
+		{"arbitrary fragment", did + "#key-1", ErrFragmentMismatch},
+		{"missing fragment", did, ErrInvalidVerificationMethodURI},
+		{"empty fragment", did + "#", ErrInvalidVerificationMethodURI},
+		{"with query", did + "?service=x#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", ErrInvalidVerificationMethodURI},
+		{"invalid DID", "did:web:example.com#key-1", ErrInvalidDIDKeyPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := SplitSigningMethodURI(tt.uri); !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package didkey
+
+import (
+	"bytes"
+)
+
+// MulticodecBytes decodes a DID key string to its multicodec-prefixed key bytes:
+// the varint key type code followed by the raw key bytes, without the multibase
+// encoding. The key is validated as Decode does.
+func MulticodecBytes(didKey string, opts ...Option) ([]byte, error) {
+	multicodecBytes, err := decodeDIDKey(didKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := decodeMulticodec(multicodecBytes, applyOptions(opts)); err != nil {
+		return nil, err
+	}
+
+	return multicodecBytes, nil
+}
+
+// FromMulticodecBytes creates a DIDKey from multicodec-prefixed key bytes, as
+// returned by MulticodecBytes. The input is copied.
+func FromMulticodecBytes(data []byte, opts ...Option) (*DIDKey, error) {
+	keyType, keyBytes, err := decodeMulticodec(data, applyOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DIDKey{keyType: keyType, keyBytes: bytes.Clone(keyBytes)}, nil
+}
+
+// MulticodecBytes returns the multicodec-prefixed key bytes of the DID key
+func (dk *DIDKey) MulticodecBytes() []byte {
+	return encodeMulticodec(dk.keyType, dk.keyBytes)
+}
+
+// CanonicalBytes returns the binary identity of the DID key for protocols
+// that hash or sign over it: the multicodec-prefixed key bytes, which the
+// fingerprint is the base58-btc encoding of. Keys are stored normalized, with
+// EC points compressed and the varint minimally encoded, so equal keys always
+// produce identical bytes, however they were constructed. It is the same as
+// MulticodecBytes.
+func (dk *DIDKey) CanonicalBytes() []byte {
+	return dk.MulticodecBytes()
+}
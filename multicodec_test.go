@@ -0,0 +1,190 @@
+package didkey
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestMulticodecBytes(t *testing.T) {
+	for name, tv := range testVectors {
+		t.Run(name, func(t *testing.T) {
+			data, err := MulticodecBytes(tv.didKey)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			keyBytes := mustDecodeHex(tv.keyHex)
+			if !bytes.HasSuffix(data, keyBytes) || len(data) <= len(keyBytes) {
+				t.Fatalf("Expected varint prefix followed by %x, got %x", keyBytes, data)
+			}
+
+			dk, err := FromMulticodecBytes(data)
+			if err != nil {
+				t.Fatalf("FromMulticodecBytes failed: %v", err)
+			}
+
+			if dk.String() != tv.didKey {
+				t.Errorf("Expected %s, got %s", tv.didKey, dk.String())
+			}
+
+			if !bytes.Equal(dk.MulticodecBytes(), data) {
+				t.Errorf("Expected %x, got %x", data, dk.MulticodecBytes())
+			}
+		})
+	}
+
+	t.Run("Ed25519 prefix", func(t *testing.T) {
+		data, err := MulticodecBytes(testVectors["Ed25519-from-spec"].didKey)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if data[0] != 0xed || data[1] != 0x01 {
+			t.Errorf("Expected 0xed01 prefix, got %x", data[:2])
+		}
+	})
+
+	t.Run("FromMulticodecBytes copies input", func(t *testing.T) {
+		data, _ := MulticodecBytes(testVectors["Ed25519-from-spec"].didKey)
+		dk, err := FromMulticodecBytes(data)
+		if err != nil {
+			t.Fatalf("FromMulticodecBytes failed: %v", err)
+		}
+
+		data[len(data)-1] ^= 0xff
+		if dk.String() != testVectors["Ed25519-from-spec"].didKey {
+			t.Errorf("Mutating the input changed the DID key")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if _, err := MulticodecBytes("did:web:example.com"); !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+			t.Errorf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+		}
+
+		if _, err := FromMulticodecBytes(nil); !errors.Is(err, ErrEmptyData) {
+			t.Errorf("Expected ErrEmptyData, got %v", err)
+		}
+
+		if _, err := FromMulticodecBytes([]byte{0xed, 0x01}); !errors.Is(err, ErrNoKeyDataAfterVarint) {
+			t.Errorf("Expected ErrNoKeyDataAfterVarint, got %v", err)
+		}
+
+		if _, err := FromMulticodecBytes([]byte{0xed, 0x01, 0x00}); !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected ErrInvalidKeySize, got %v", err)
+		}
+	})
+}
+
+func TestCanonicalBytes(t *testing.T) {
+	const generator = "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	compressed := mustDecodeHex("02" + generator)
+	uncompressed := mustDecodeHex("04" + generator + "483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+
+	a, err := FromBytes(Secp256k1PublicKey, compressed)
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+	b, err := FromBytes(Secp256k1PublicKey, compressed)
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+	fromUncompressed, err := FromBytes(Secp256k1PublicKey, uncompressed)
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+	parsed, err := Parse(a.String())
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := append([]byte{0xe7, 0x01}, compressed...)
+	for name, dk := range map[string]*DIDKey{"same inputs": b, "uncompressed": fromUncompressed, "parsed": parsed, "original": a} {
+		if got := dk.CanonicalBytes(); !bytes.Equal(got, expected) {
+			t.Errorf("%s: expected %x, got %x", name, expected, got)
+		}
+	}
+
+	// The fingerprint is the base58-btc encoding of the canonical bytes
+	if fingerprint := "z" + encodeBase58BTC(a.CanonicalBytes()); fingerprint != a.Fingerprint() {
+		t.Errorf("Expected fingerprint %s, got %s", a.Fingerprint(), fingerprint)
+	}
+
+	// The result is a copy
+	a.CanonicalBytes()[0] = 0
+	if got := a.CanonicalBytes(); !bytes.Equal(got, expected) {
+		t.Errorf("Expected CanonicalBytes to return a copy, got %x", got)
+	}
+}
+
+func TestMulticodecVarintLengths(t *testing.T) {
+	// Supported codes all take two varint bytes; codes of key types registered
+	// later may take three or more
+	tests := []struct {
+		name   string
+		code   uint64
+		varint []byte
+	}{
+		{name: "largest one-byte code", code: 0x7f, varint: []byte{0x7f}},
+		{name: "smallest two-byte code", code: 0x80, varint: []byte{0x80, 0x01}},
+		{name: "two-byte code", code: 0x1337, varint: []byte{0xb7, 0x26}},
+		{name: "largest two-byte code", code: 0x3fff, varint: []byte{0xff, 0x7f}},
+		{name: "smallest three-byte code", code: 0x4000, varint: []byte{0x80, 0x80, 0x01}},
+		{name: "three-byte code", code: 0x1a2b3c, varint: []byte{0xbc, 0xd6, 0x68}},
+		{name: "largest three-byte code", code: 0x1fffff, varint: []byte{0xff, 0xff, 0x7f}},
+		{name: "smallest four-byte code", code: 0x200000, varint: []byte{0x80, 0x80, 0x80, 0x01}},
+	}
+
+	keyBytes := []byte{1, 2, 3}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := append(bytes.Clone(tt.varint), keyBytes...)
+
+			data := encodeMulticodec(KeyType(tt.code), keyBytes)
+			if !bytes.Equal(data, expected) {
+				t.Fatalf("Expected multicodec bytes %x, got %x", expected, data)
+			}
+
+			keyType, gotKeyBytes, err := splitMulticodec(data)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if uint64(keyType) != tt.code || !bytes.Equal(gotKeyBytes, keyBytes) {
+				t.Errorf("Expected code %#x and key %x, got %#x and %x", tt.code, keyBytes, uint64(keyType), gotKeyBytes)
+			}
+
+			// Through the DID key string and back
+			didKey, err := EncodeWithCodec(tt.code, keyBytes)
+			if err != nil {
+				t.Fatalf("EncodeWithCodec failed: %v", err)
+			}
+			decoded, err := decodeMultibase(didKey[len(DIDKeyPrefix):])
+			if err != nil {
+				t.Fatalf("Failed to decode multibase: %v", err)
+			}
+			if !bytes.Equal(decoded, expected) {
+				t.Errorf("Expected multicodec bytes %x, got %x", expected, decoded)
+			}
+		})
+	}
+
+	t.Run("varint only", func(t *testing.T) {
+		if _, _, err := splitMulticodec([]byte{0x80, 0x80, 0x01}); !errors.Is(err, ErrNoKeyDataAfterVarint) {
+			t.Errorf("Expected ErrNoKeyDataAfterVarint, got %v", err)
+		}
+	})
+
+	t.Run("truncated varint", func(t *testing.T) {
+		if _, _, err := splitMulticodec([]byte{0x80, 0x80}); !errors.Is(err, ErrInvalidVarint) {
+			t.Errorf("Expected ErrInvalidVarint, got %v", err)
+		}
+	})
+
+	t.Run("non-minimal varint", func(t *testing.T) {
+		// 0xed encoded in three bytes instead of two
+		if _, _, err := splitMulticodec([]byte{0xed, 0x81, 0x00, 1}); !errors.Is(err, ErrInvalidVarint) {
+			t.Errorf("Expected ErrInvalidVarint, got %v", err)
+		}
+	})
+}
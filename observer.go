@@ -0,0 +1,95 @@
+package didkey
+
+import (
+	"errors"
+)
+
+// DecodeObserver is notified of decode failures, e.g. to count them in metrics.
+// Set it with WithDecodeObserver.
+type DecodeObserver interface {
+	// OnError is called with the category of the error, one of the
+	// DecodeError* constants, before the error is returned
+	OnError(category string)
+}
+
+// Decode failure categories reported to a DecodeObserver. They are stable and
+// suitable as metric label values.
+const (
+	DecodeErrorInvalidUTF8        = "invalid_utf8"
+	DecodeErrorControlCharacter   = "control_character"
+	DecodeErrorNonASCII           = "non_ascii"
+	DecodeErrorInvalidPrefix      = "invalid_prefix"
+	DecodeErrorEmptyFingerprint   = "empty_fingerprint"
+	DecodeErrorInvalidLength      = "invalid_length"
+	DecodeErrorBadBase58          = "bad_base58"
+	DecodeErrorUnknownMultibase   = "unknown_multibase"
+	DecodeErrorNotBase58BTC       = "not_base58btc"
+	DecodeErrorBadMultibase       = "bad_multibase"
+	DecodeErrorEmptyData          = "empty_data"
+	DecodeErrorInvalidVarint      = "invalid_varint"
+	DecodeErrorNoKeyData          = "no_key_data"
+	DecodeErrorUnsupportedType    = "unsupported_type"
+	DecodeErrorInvalidKeySize     = "invalid_key_size"
+	DecodeErrorInvalidPointPrefix = "invalid_point_prefix"
+	DecodeErrorInvalidPoint       = "invalid_point"
+	DecodeErrorInvalidBLSEncoding = "invalid_bls_encoding"
+	DecodeErrorSmallOrder         = "small_order"
+	DecodeErrorAllZero            = "all_zero"
+	DecodeErrorOther              = "other"
+)
+
+// decodeErrorCategories maps decode errors to their categories. Errors that
+// wrap others come first, e.g. ErrInvalidBase58Character before
+// ErrMultibaseDecodeFailed, or ErrInvalidBLSEncoding before ErrInvalidKeySize
+// for uncompressed BLS12-381 keys.
+var decodeErrorCategories = []struct {
+	err      error
+	category string
+}{
+	{ErrInvalidUTF8, DecodeErrorInvalidUTF8},
+	{ErrControlCharacter, DecodeErrorControlCharacter},
+	{ErrNonASCII, DecodeErrorNonASCII},
+	{ErrInvalidDIDKeyPrefix, DecodeErrorInvalidPrefix},
+	{ErrEmptyMultibaseString, DecodeErrorEmptyFingerprint},
+	{ErrInvalidFingerprintLength, DecodeErrorInvalidLength},
+	{ErrInvalidBase58Character, DecodeErrorBadBase58},
+	{ErrUnknownMultibasePrefix, DecodeErrorUnknownMultibase},
+	{ErrExpectedBase58BTC, DecodeErrorNotBase58BTC},
+	{ErrMultibaseDecodeFailed, DecodeErrorBadMultibase},
+	{ErrEmptyData, DecodeErrorEmptyData},
+	{ErrInvalidVarint, DecodeErrorInvalidVarint},
+	{ErrNoKeyDataAfterVarint, DecodeErrorNoKeyData},
+	{ErrUnsupportedKeyType, DecodeErrorUnsupportedType},
+	{ErrInvalidBLSEncoding, DecodeErrorInvalidBLSEncoding},
+	{ErrInvalidKeySize, DecodeErrorInvalidKeySize},
+	{ErrInvalidCompressedPrefix, DecodeErrorInvalidPointPrefix},
+	{ErrInvalidPoint, DecodeErrorInvalidPoint},
+	{ErrSmallOrderKey, DecodeErrorSmallOrder},
+	{ErrAllZeroKey, DecodeErrorAllZero},
+}
+
+// WithDecodeObserver reports decode failures to observer. Without an observer,
+// failures cost nothing extra.
+func WithDecodeObserver(observer DecodeObserver) Option {
+	return func(o *options) {
+		o.decodeObserver = observer
+	}
+}
+
+// observeDecodeError reports a decode error, if any, to the observer, if set
+func (o options) observeDecodeError(err error) {
+	if err == nil || o.decodeObserver == nil {
+		return
+	}
+
+	o.decodeObserver.OnError(decodeErrorCategory(err))
+}
+
+func decodeErrorCategory(err error) string {
+	for _, c := range decodeErrorCategories {
+		if errors.Is(err, c.err) {
+			return c.category
+		}
+	}
+	return DecodeErrorOther
+}
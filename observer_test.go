@@ -0,0 +1,101 @@
+package didkey
+
+import (
+	"bytes"
+	"encoding/hex"
+	"slices"
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	categories []string
+}
+
+func (r *recordingObserver) OnError(category string) {
+	r.categories = append(r.categories, category)
+}
+
+func TestDecodeObserver(t *testing.T) {
+	fingerprint := "z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+	fromMulticodec := func(codec []byte, keyBytes []byte) string {
+		return DIDKeyPrefix + "z" + encodeBase58BTC(append(slices.Clone(codec), keyBytes...))
+	}
+	p384 := encodeMulticodec(P384PublicKey, mustDecodeHex(p384GeneratorHex))
+
+	// ErrEmptyData and ErrNoKeyDataAfterVarint cannot be reached through Decode,
+	// whose fingerprint length check rejects such short input first
+	tests := []struct {
+		category string
+		didKey   string
+		opts     []Option
+	}{
+		{category: DecodeErrorInvalidUTF8, didKey: DIDKeyPrefix + "\xff" + fingerprint},
+		{category: DecodeErrorControlCharacter, didKey: DIDKeyPrefix + fingerprint + "\x00"},
+		{category: DecodeErrorNonASCII, didKey: DIDKeyPrefix + fingerprint + "é"},
+		{category: DecodeErrorInvalidPrefix, didKey: "did:web:" + fingerprint},
+		{category: DecodeErrorEmptyFingerprint, didKey: DIDKeyPrefix},
+		{category: DecodeErrorInvalidLength, didKey: DIDKeyPrefix + "z6Mk"},
+		{category: DecodeErrorBadBase58, didKey: DIDKeyPrefix + "z6Mkha0gBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"},
+		{category: DecodeErrorUnknownMultibase, didKey: DIDKeyPrefix + "!" + fingerprint[1:]},
+		{category: DecodeErrorNotBase58BTC, didKey: DIDKeyPrefix + "f" + hex.EncodeToString(p384)},
+		{category: DecodeErrorBadMultibase, didKey: DIDKeyPrefix + "f" + fingerprint},
+		{category: DecodeErrorInvalidVarint, didKey: fromMulticodec([]byte{0xed, 0x81, 0x00}, make([]byte, 32))},
+		{category: DecodeErrorUnsupportedType, didKey: fromMulticodec([]byte{0x01}, make([]byte, 34))},
+		{category: DecodeErrorInvalidKeySize, didKey: fromMulticodec([]byte{0xed, 0x01}, make([]byte, 40))},
+		{category: DecodeErrorInvalidPointPrefix, didKey: fromMulticodec([]byte{0xe7, 0x01}, append([]byte{0x05}, make([]byte, 32)...))},
+		{category: DecodeErrorInvalidPoint, didKey: fromMulticodec([]byte{0x80, 0x24}, append([]byte{0x02}, bytes.Repeat([]byte{0xff}, 32)...))},
+		{category: DecodeErrorInvalidBLSEncoding, didKey: fromMulticodec([]byte{0xea, 0x01}, append([]byte{0x17}, bytes.Repeat([]byte{0xff}, 47)...))},
+		{category: DecodeErrorInvalidBLSEncoding, didKey: fromMulticodec([]byte{0xea, 0x01}, append([]byte{0xc0}, make([]byte, 47)...))},
+		{category: DecodeErrorInvalidBLSEncoding, didKey: fromMulticodec([]byte{0xea, 0x01}, append([]byte{0x17}, make([]byte, 95)...))},
+		{
+			category: DecodeErrorSmallOrder,
+			didKey:   fromMulticodec([]byte{0xed, 0x01}, mustDecodeHex("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")),
+			opts:     []Option{WithMode(ModeStrict)},
+		},
+		{category: DecodeErrorAllZero, didKey: fromMulticodec([]byte{0xec, 0x01}, make([]byte, 32)), opts: []Option{WithRejectAllZero(true)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			observer := &recordingObserver{}
+			opts := append(tt.opts, WithDecodeObserver(observer))
+
+			if _, _, err := Decode(tt.didKey, opts...); err == nil {
+				t.Fatalf("Expected error for %q", tt.didKey)
+			}
+			if !slices.Equal(observer.categories, []string{tt.category}) {
+				t.Errorf("Expected [%s], got %v", tt.category, observer.categories)
+			}
+
+			// DecodeWithHooks reports through its own decoding path
+			observer.categories = nil
+			hooks := DecodeHooks{AfterValidation: func(time.Duration) {}}
+			if _, _, err := DecodeWithHooks(tt.didKey, hooks, opts...); err == nil {
+				t.Fatalf("Expected DecodeWithHooks error for %q", tt.didKey)
+			}
+			if len(observer.categories) != 1 {
+				t.Errorf("Expected one DecodeWithHooks category, got %v", observer.categories)
+			}
+		})
+	}
+
+	t.Run("uncompressed BLS12-381 G2", func(t *testing.T) {
+		// Decode rejects the fingerprint of a 192-byte key by length first
+		keyBytes := append([]byte{0x17}, make([]byte, 191)...)
+		_, err := FromMulticodecBytes(encodeMulticodec(Bls12381G2PublicKey, keyBytes))
+		if category := decodeErrorCategory(err); category != DecodeErrorInvalidBLSEncoding {
+			t.Errorf("Expected %s, got %s for %v", DecodeErrorInvalidBLSEncoding, category, err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		observer := &recordingObserver{}
+		if _, err := Parse(DIDKeyPrefix+fingerprint, WithDecodeObserver(observer)); err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if len(observer.categories) != 0 {
+			t.Errorf("Expected no categories, got %v", observer.categories)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package didkey
+
+import (
+	"crypto"
+	"sync/atomic"
+)
+
+// Option configures the behavior of Encode and Decode
+type Option func(*options)
+
+type options struct {
+	curveValidation  bool
+	continueOnError  bool
+	blsDSTOverride   []byte
+	hash             crypto.Hash
+	leftPad          bool
+	rejectSmallOrder bool
+	rejectAllZero    bool
+	decodeObserver   DecodeObserver
+
+	alsoKnownAsDerived bool
+	relativeMethodIDs  bool
+
+	maxInputBytes int64
+	maxLineLength int
+}
+
+// BLS hash-to-curve domain separation tags of the basic (NUL) ciphersuites
+const (
+	DefaultBLSG1DST = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_"
+	DefaultBLSG2DST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_"
+)
+
+func applyOptions(opts []Option) options {
+	var o options
+	o.setMode(Mode(defaultMode.Load()))
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithCurveValidation enables or disables the on-curve check performed for
+// secp256k1, P-256 and P-384 keys. Validation is enabled by default.
+//
+// SECURITY: disabling validation means malformed or malicious EC keys are
+// encoded and decoded without complaint, and invalid-curve points may reach
+// downstream cryptographic code. Only disable it for did:keys from a trusted
+// source where the cost of the check matters.
+func WithCurveValidation(enabled bool) Option {
+	return func(o *options) {
+		o.curveValidation = enabled
+	}
+}
+
+// WithContinueOnError makes EncodeAll and DecodeAll process every item instead
+// of stopping at the first failure
+func WithContinueOnError() Option {
+	return func(o *options) {
+		o.continueOnError = true
+	}
+}
+
+// WithBLSDST overrides the hash-to-curve domain separation tag used by BLS
+// signature verification (see VerifyBLS)
+func WithBLSDST(dst string) Option {
+	return func(o *options) {
+		o.blsDSTOverride = []byte(dst)
+	}
+}
+
+// WithHash overrides the hash of the message that Verify checks secp256k1,
+// P-256 and P-384 signatures over, for protocols that pair a curve with a
+// non-default hash, e.g. P-256 with SHA-512. Digests longer than the curve
+// order are truncated, as in SEC 1. The hash must be linked into the binary
+// and have a digest of at least 256 bits, otherwise Verify returns
+// ErrUnsupportedHash. A zero hash restores the default; Ed25519 and BLS12-381
+// keys, and VerifyJWS and VerifyCredentialProof, whose hashes are fixed by
+// their algorithms, ignore it.
+func WithHash(hash crypto.Hash) Option {
+	return func(o *options) {
+		o.hash = hash
+	}
+}
+
+func (o options) blsDST(fallback string) []byte {
+	if o.blsDSTOverride != nil {
+		return o.blsDSTOverride
+	}
+	return []byte(fallback)
+}
+
+// WithLeftPad makes Encode and FromBytes left-pad Ed25519 keys shorter than 32
+// bytes with zero bytes, to rescue keys from broken libraries that strip
+// leading zeros. Longer keys are still rejected.
+//
+// This is a compatibility shim, not part of the DID Key specification: a
+// padded key is only correct if the bytes were lost exactly this way.
+func WithLeftPad() Option {
+	return func(o *options) {
+		o.leftPad = true
+	}
+}
+
+// WithRejectAllZero enables or disables the rejection of keys whose bytes,
+// after the compressed point prefix of EC keys, are all zero. Such a key is
+// never a real public key and usually means an uninitialized buffer was
+// encoded. It is disabled by default and enabled in ModeStrict.
+func WithRejectAllZero(enabled bool) Option {
+	return func(o *options) {
+		o.rejectAllZero = enabled
+	}
+}
+
+// WithAlsoKnownAsDerived makes ResolveDocument list the X25519 DID key derived
+// from an Ed25519 DID key, which serves its keyAgreement relationship, in the
+// document's alsoKnownAs. Documents of other key types are unchanged.
+func WithAlsoKnownAsDerived() Option {
+	return func(o *options) {
+		o.alsoKnownAsDerived = true
+	}
+}
+
+// WithRelativeMethodIDs makes ResolveDocument render verification method ids,
+// and the relationships referencing them, relative to the document id
+// (#z6Mk...) rather than absolute (did:key:z6Mk...#z6Mk...), as some
+// Verifiable Credential libraries require. Controllers stay the DID key itself.
+func WithRelativeMethodIDs() Option {
+	return func(o *options) {
+		o.relativeMethodIDs = true
+	}
+}
+
+// WithInputLimits bounds the input read by DecodeAllFrom: at most maxBytes in
+// total and maxLineLength bytes per line, excluding the line ending. A zero
+// or negative limit keeps its default, DefaultMaxInputBytes or
+// DefaultMaxLineLength.
+func WithInputLimits(maxBytes int64, maxLineLength int) Option {
+	return func(o *options) {
+		o.maxInputBytes = maxBytes
+		o.maxLineLength = maxLineLength
+	}
+}
+
+// Mode is a validation profile, grouping the checks that reject keys earlier
+// versions of this package accepted
+type Mode int32
+
+const (
+	// ModeCompatible is the default mode. EC keys are checked to be on their
+	// curve and multicodec varints must be minimally encoded; Ed25519 keys are
+	// only checked for size.
+	ModeCompatible Mode = iota
+
+	// ModeStrict adds to ModeCompatible the rejection of Ed25519 keys of small
+	// order, including their non-canonical encodings, and of all-zero keys (see
+	// WithRejectAllZero). Such keys are never produced by key generation, but
+	// may be crafted to make signatures verify for an attacker-chosen key.
+	ModeStrict
+)
+
+var defaultMode atomic.Int32
+
+// SetDefaultMode sets the mode used by every call that does not pass
+// WithMode. It is safe for concurrent use, but is meant to be called once at
+// program start.
+func SetDefaultMode(mode Mode) {
+	defaultMode.Store(int32(mode))
+}
+
+// WithMode selects the validation mode for a single call, overriding the
+// default mode. It resets the checks of the mode, so it should come before
+// options such as WithCurveValidation that adjust them.
+func WithMode(mode Mode) Option {
+	return func(o *options) {
+		o.setMode(mode)
+	}
+}
+
+func (o *options) setMode(mode Mode) {
+	o.curveValidation = true
+	o.rejectSmallOrder = mode == ModeStrict
+	o.rejectAllZero = mode == ModeStrict
+}
@@ -0,0 +1,222 @@
+package didkey
+
+import (
+	"errors"
+	"testing"
+)
+
+// Compressed P-384 generator point
+const p384GeneratorHex = "03aa87ca22be8b05378eb1c71ef320ad746e1d3b628ba79b9859f741e082542a385502f25dbf55296c3a545e3872760ab7"
+
+func TestWithCurveValidation(t *testing.T) {
+	// x = 1 has no corresponding y on P-256
+	offCurve := make([]byte, 33)
+	offCurve[0] = 0x02
+	offCurve[32] = 0x01
+
+	t.Run("enabled by default", func(t *testing.T) {
+		_, err := Encode(P256PublicKey, offCurve)
+		if !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("Expected ErrInvalidPoint, got %v", err)
+		}
+	})
+
+	t.Run("disabled on encode", func(t *testing.T) {
+		_, err := Encode(P256PublicKey, offCurve, WithCurveValidation(false))
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("disabled on decode", func(t *testing.T) {
+		didKey, err := Encode(P256PublicKey, offCurve, WithCurveValidation(false))
+		if err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+
+		if _, _, err := Decode(didKey); !errors.Is(err, ErrInvalidPoint) {
+			t.Errorf("Expected ErrInvalidPoint, got %v", err)
+		}
+
+		if _, _, err := Decode(didKey, WithCurveValidation(false)); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("valid P-384 point", func(t *testing.T) {
+		_, err := Encode(P384PublicKey, mustDecodeHex(p384GeneratorHex))
+		if err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func BenchmarkDecodeP384(b *testing.B) {
+	didKey, err := Encode(P384PublicKey, mustDecodeHex(p384GeneratorHex))
+	if err != nil {
+		b.Fatalf("Encode failed: %v", err)
+	}
+
+	b.Run("validation on", func(b *testing.B) {
+		for b.Loop() {
+			if _, _, err := Decode(didKey); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("validation off", func(b *testing.B) {
+		for b.Loop() {
+			if _, _, err := Decode(didKey, WithCurveValidation(false)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestWithLeftPad(t *testing.T) {
+	full := mustDecodeHex("00" + "5a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	short := full[1:]
+
+	t.Run("strict by default", func(t *testing.T) {
+		if _, err := Encode(Ed25519PublicKey, short); !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected ErrInvalidKeySize, got %v", err)
+		}
+	})
+
+	t.Run("pads short key", func(t *testing.T) {
+		expected, err := Encode(Ed25519PublicKey, full)
+		if err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+
+		didKey, err := Encode(Ed25519PublicKey, short, WithLeftPad())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if didKey != expected {
+			t.Errorf("Expected %s, got %s", expected, didKey)
+		}
+
+		dk, err := FromBytes(Ed25519PublicKey, short, WithLeftPad())
+		if err != nil {
+			t.Fatalf("FromBytes failed: %v", err)
+		}
+
+		if dk.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, dk.String())
+		}
+	})
+
+	t.Run("rejects long key", func(t *testing.T) {
+		if _, err := Encode(Ed25519PublicKey, make([]byte, 33), WithLeftPad()); !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected ErrInvalidKeySize, got %v", err)
+		}
+	})
+
+	t.Run("only Ed25519", func(t *testing.T) {
+		if _, err := Encode(X25519PublicKey, make([]byte, 31), WithLeftPad()); !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected ErrInvalidKeySize, got %v", err)
+		}
+	})
+}
+
+func TestMode(t *testing.T) {
+	t.Cleanup(func() { SetDefaultMode(ModeCompatible) })
+
+	// The point of order 8 with y = 0x7a03...17c7, and a non-canonical
+	// encoding (y = p + 1) of the identity
+	smallOrder := mustDecodeHex("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+	nonCanonical := mustDecodeHex("eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	valid := mustDecodeHex(testVectors["Ed25519-from-spec"].keyHex)
+
+	t.Run("compatible accepts small order", func(t *testing.T) {
+		for _, keyBytes := range [][]byte{smallOrder, nonCanonical, make([]byte, 32)} {
+			if _, err := Encode(Ed25519PublicKey, keyBytes); err != nil {
+				t.Errorf("Unexpected error for %x: %v", keyBytes, err)
+			}
+		}
+	})
+
+	t.Run("strict rejects small order", func(t *testing.T) {
+		for _, keyBytes := range [][]byte{smallOrder, nonCanonical, make([]byte, 32)} {
+			if _, err := Encode(Ed25519PublicKey, keyBytes, WithMode(ModeStrict)); !errors.Is(err, ErrSmallOrderKey) {
+				t.Errorf("Expected ErrSmallOrderKey for %x, got %v", keyBytes, err)
+			}
+		}
+
+		if _, err := Encode(Ed25519PublicKey, valid, WithMode(ModeStrict)); err != nil {
+			t.Errorf("Unexpected error for a valid key: %v", err)
+		}
+	})
+
+	t.Run("default mode", func(t *testing.T) {
+		didKey, err := Encode(Ed25519PublicKey, smallOrder)
+		if err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+
+		SetDefaultMode(ModeStrict)
+		if _, _, err := Decode(didKey); !errors.Is(err, ErrSmallOrderKey) {
+			t.Errorf("Expected ErrSmallOrderKey in strict default mode, got %v", err)
+		}
+
+		// Per-call options override the default
+		if _, _, err := Decode(didKey, WithMode(ModeCompatible)); err != nil {
+			t.Errorf("Unexpected error with WithMode(ModeCompatible): %v", err)
+		}
+
+		SetDefaultMode(ModeCompatible)
+		if _, _, err := Decode(didKey); err != nil {
+			t.Errorf("Unexpected error in compatible default mode: %v", err)
+		}
+	})
+
+	t.Run("non-minimal varint in both modes", func(t *testing.T) {
+		// 0xed encoded as three varint bytes instead of two
+		data := append([]byte{0xed, 0x81, 0x00}, valid...)
+		for _, mode := range []Mode{ModeCompatible, ModeStrict} {
+			if _, err := FromMulticodecBytes(data, WithMode(mode)); !errors.Is(err, ErrInvalidVarint) {
+				t.Errorf("Expected ErrInvalidVarint in mode %d, got %v", mode, err)
+			}
+		}
+	})
+}
+
+func TestWithRejectAllZero(t *testing.T) {
+	zeroEd25519 := make([]byte, 32)
+	zeroP256 := append([]byte{0x02}, make([]byte, 32)...)
+	valid := mustDecodeHex(testVectors["Ed25519-from-spec"].keyHex)
+
+	tests := []struct {
+		name        string
+		keyType     KeyType
+		keyBytes    []byte
+		opts        []Option
+		expectedErr error
+	}{
+		{name: "zero Ed25519 accepted by default", keyType: Ed25519PublicKey, keyBytes: zeroEd25519},
+		{name: "zero Ed25519 rejected", keyType: Ed25519PublicKey, keyBytes: zeroEd25519, opts: []Option{WithRejectAllZero(true)}, expectedErr: ErrAllZeroKey},
+		{name: "zero X25519 rejected", keyType: X25519PublicKey, keyBytes: zeroEd25519, opts: []Option{WithRejectAllZero(true)}, expectedErr: ErrAllZeroKey},
+		{name: "zero P-256 after prefix rejected", keyType: P256PublicKey, keyBytes: zeroP256, opts: []Option{WithCurveValidation(false), WithRejectAllZero(true)}, expectedErr: ErrAllZeroKey},
+		{name: "zero X25519 rejected in strict mode", keyType: X25519PublicKey, keyBytes: zeroEd25519, opts: []Option{WithMode(ModeStrict)}, expectedErr: ErrAllZeroKey},
+		{name: "strict mode disabled", keyType: X25519PublicKey, keyBytes: zeroEd25519, opts: []Option{WithMode(ModeStrict), WithRejectAllZero(false)}},
+		{name: "valid key accepted", keyType: Ed25519PublicKey, keyBytes: valid, opts: []Option{WithRejectAllZero(true)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Encode(tt.keyType, tt.keyBytes, tt.opts...)
+			if tt.expectedErr == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
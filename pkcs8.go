@@ -0,0 +1,35 @@
+package didkey
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+)
+
+// FromPKCS8 returns the DID key of the public key of a PKCS #8 DER-encoded
+// private key, as exported by many key stores. The algorithms Go parses are
+// supported as by FromSigner, using the public key of the private key:
+//   - Ed25519 and X25519
+//   - ECDSA on P-256 or P-384
+//
+// Other algorithms, such as RSA, return ErrUnsupportedPrivateKey. DER that is
+// not PKCS #8, or uses a curve Go does not parse (including secp256k1),
+// returns ErrInvalidPKCS8.
+func FromPKCS8(der []byte, opts ...Option) (*DIDKey, error) {
+	priv, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, ErrInvalidPKCS8WithContext(err)
+	}
+
+	// Every private key type of crypto/x509 has a Public method
+	key, ok := priv.(interface{ Public() crypto.PublicKey })
+	if !ok {
+		return nil, ErrUnsupportedPrivateKeyWithContext(priv)
+	}
+
+	dk, err := fromPublicKey(key.Public(), opts)
+	if errors.Is(err, ErrUnsupportedKeyType) {
+		return nil, ErrUnsupportedPrivateKeyWithContext(priv)
+	}
+	return dk, err
+}
@@ -0,0 +1,84 @@
+package didkey
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestFromPKCS8(t *testing.T) {
+	tests := []struct {
+		name    string
+		der     string // base64
+		keyType KeyType
+		keyHex  string
+	}{
+		{
+			// RFC 8410, section 10.3
+			name:    "Ed25519",
+			der:     "MC4CAQAwBQYDK2VwBCIEINTuctv5E1hK1bbY8fdp+K06/nwoy/HU++CXqI9EdVhC",
+			keyType: Ed25519PublicKey,
+			keyHex:  "19bf44096984cdfe8541bac167dc3b96c85086aa30b6b6cb0c5c38ad703166e1",
+		},
+		{
+			name:    "P-256",
+			der:     "MIGHAgEAMBMGByqGSM49AgEGCCqGSM49AwEHBG0wawIBAQQg87qcYT326YrpwBq2SUOO+u+bnWnMaW/UFNW/5ifejeqhRANCAAQzHCtisTVbrNiZWxrSIHpffs2m99vK0JT3mLhCsBjpcoJGoFFTN3z0O2VxnaKzEA+f8+9j242fbhFPd08XMYpH",
+			keyType: P256PublicKey,
+			keyHex:  "03331c2b62b1355bacd8995b1ad2207a5f7ecda6f7dbcad094f798b842b018e972",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			der, err := base64.StdEncoding.DecodeString(tt.der)
+			if err != nil {
+				t.Fatalf("Invalid fixture: %v", err)
+			}
+
+			dk, err := FromPKCS8(der)
+			if err != nil {
+				t.Fatalf("FromPKCS8 failed: %v", err)
+			}
+
+			expected, err := FromBytes(tt.keyType, mustDecodeHex(tt.keyHex))
+			if err != nil {
+				t.Fatalf("FromBytes failed: %v", err)
+			}
+			if dk.String() != expected.String() {
+				t.Errorf("Expected %s, got %s", expected, dk)
+			}
+		})
+	}
+}
+
+func TestFromPKCS8Errors(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	rsaDER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		der         []byte
+		expectedErr error
+	}{
+		{name: "RSA", der: rsaDER, expectedErr: ErrUnsupportedPrivateKey},
+		{name: "garbage", der: []byte{0x30, 0x03, 0x02, 0x01}, expectedErr: ErrInvalidPKCS8},
+		{name: "empty", der: nil, expectedErr: ErrInvalidPKCS8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromPKCS8(tt.der); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package didkey
+
+// DID Resolution error codes (https://w3c.github.io/did-resolution/#errors)
+const (
+	ResolutionInvalidDID    = "invalidDid"
+	ResolutionInvalidDIDURL = "invalidDidUrl"
+	ResolutionNotFound      = "notFound"
+
+	// Returned when dereferencing a service that the DID Document does not have
+	ResolutionServiceNotFound = "serviceNotFound"
+
+	// Returned when dereferencing a service with a DID Document of another DID
+	ResolutionDocumentMismatch = "documentMismatch"
+
+	// Returned when dereferencing a service whose endpoint is not an absolute URL
+	ResolutionInvalidServiceEndpoint = "invalidServiceEndpoint"
+
+	// Defined by the DID Key specification
+	ResolutionInvalidPublicKey = "invalidPublicKey"
+)
+
+// ResolutionError is an error returned by DID key resolution, carrying the
+// DID Resolution error code alongside the underlying error
+type ResolutionError struct {
+	Code string
+	Err  error
+}
+
+func (e *ResolutionError) Error() string {
+	return e.Code + ": " + e.Err.Error()
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
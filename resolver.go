@@ -0,0 +1,130 @@
+package didkey
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Resolver resolves a DID, or the DID of a DID URL, to its DID Document
+type Resolver interface {
+	Resolve(did string) (*Document, error)
+}
+
+// NewResolver returns a Resolver that resolves DID keys with ResolveDocument
+// and the given options
+func NewResolver(opts ...Option) Resolver {
+	return keyResolver{opts: opts}
+}
+
+type keyResolver struct {
+	opts []Option
+}
+
+func (r keyResolver) Resolve(did string) (*Document, error) {
+	return ResolveDocument(did, r.opts...)
+}
+
+// CachingResolver is a Resolver caching the documents of a base Resolver in an
+// LRU cache. Resolved documents are cloned into and out of the cache, so callers
+// cannot mutate cached state. Errors are not cached. It is safe for concurrent use.
+//
+// DID key documents never change, so the optional TTL only bounds how long
+// entries hold memory; the capacity bounds how many they are.
+type CachingResolver struct {
+	base     Resolver
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // Most recently used first
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	did     string
+	doc     *Document
+	expires time.Time
+}
+
+// NewCachingResolver returns a CachingResolver holding at most capacity
+// documents of base, each for at most ttl, or without expiry if ttl is zero.
+// It panics if capacity is not positive.
+func NewCachingResolver(base Resolver, capacity int, ttl time.Duration) *CachingResolver {
+	if capacity <= 0 {
+		panic("didkey: non-positive CachingResolver capacity")
+	}
+
+	return &CachingResolver{
+		base:     base,
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Resolve returns the cached document of did, resolving it with the base
+// Resolver on a miss. Concurrent misses for the same DID may each resolve it.
+func (r *CachingResolver) Resolve(did string) (*Document, error) {
+	if doc, ok := r.get(did); ok {
+		return doc, nil
+	}
+
+	doc, err := r.base.Resolve(did)
+	if err != nil {
+		return nil, err
+	}
+
+	r.put(did, doc.Clone())
+	return doc, nil
+}
+
+// Len returns the number of cached documents, including expired ones not yet
+// evicted
+func (r *CachingResolver) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.order.Len()
+}
+
+func (r *CachingResolver) get(did string) (*Document, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	element, ok := r.entries[did]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if r.ttl > 0 && !r.now().Before(entry.expires) {
+		r.order.Remove(element)
+		delete(r.entries, did)
+		return nil, false
+	}
+
+	r.order.MoveToFront(element)
+	return entry.doc.Clone(), true
+}
+
+func (r *CachingResolver) put(did string, doc *Document) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := &cacheEntry{did: did, doc: doc, expires: r.now().Add(r.ttl)}
+	if element, ok := r.entries[did]; ok {
+		element.Value = entry
+		r.order.MoveToFront(element)
+		return
+	}
+
+	r.entries[did] = r.order.PushFront(entry)
+	if r.order.Len() > r.capacity {
+		oldest := r.order.Back()
+		r.order.Remove(oldest)
+		delete(r.entries, oldest.Value.(*cacheEntry).did)
+	}
+}
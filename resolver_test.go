@@ -0,0 +1,195 @@
+package didkey
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingResolver resolves DID keys, counting the calls that reach it
+type countingResolver struct {
+	calls atomic.Int32
+}
+
+func (r *countingResolver) Resolve(did string) (*Document, error) {
+	r.calls.Add(1)
+	return ResolveDocument(did)
+}
+
+func TestCachingResolver(t *testing.T) {
+	ed25519DID := testVectors["Ed25519-from-spec"].didKey
+	p256DID := testVectors["P-256-test"].didKey
+	secp256k1DID := testVectors["Secp256k1-test"].didKey
+
+	t.Run("cache hit", func(t *testing.T) {
+		base := &countingResolver{}
+		resolver := NewCachingResolver(base, 2, 0)
+
+		first, err := resolver.Resolve(ed25519DID)
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		second, err := resolver.Resolve(ed25519DID)
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+
+		if calls := base.calls.Load(); calls != 1 {
+			t.Errorf("Expected 1 base resolution, got %d", calls)
+		}
+		if !first.Equal(second) {
+			t.Errorf("Expected equal documents, got %+v and %+v", first, second)
+		}
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		base := &countingResolver{}
+		resolver := NewCachingResolver(base, 2, 0)
+
+		// ed25519DID is used again before secp256k1DID is added, so p256DID is evicted
+		for _, did := range []string{ed25519DID, p256DID, ed25519DID, secp256k1DID} {
+			if _, err := resolver.Resolve(did); err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+		}
+		if calls := base.calls.Load(); calls != 3 {
+			t.Fatalf("Expected 3 base resolutions, got %d", calls)
+		}
+		if n := resolver.Len(); n != 2 {
+			t.Errorf("Expected 2 cached documents, got %d", n)
+		}
+
+		for _, did := range []string{ed25519DID, secp256k1DID} {
+			if _, err := resolver.Resolve(did); err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+		}
+		if calls := base.calls.Load(); calls != 3 {
+			t.Errorf("Expected cached documents to be kept, got %d base resolutions", calls)
+		}
+
+		if _, err := resolver.Resolve(p256DID); err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if calls := base.calls.Load(); calls != 4 {
+			t.Errorf("Expected the evicted document to be resolved again, got %d base resolutions", calls)
+		}
+	})
+
+	t.Run("TTL", func(t *testing.T) {
+		base := &countingResolver{}
+		resolver := NewCachingResolver(base, 2, time.Minute)
+		now := time.Unix(0, 0)
+		resolver.now = func() time.Time { return now }
+
+		for _, elapsed := range []time.Duration{0, 59 * time.Second, time.Minute} {
+			now = time.Unix(0, 0).Add(elapsed)
+			if _, err := resolver.Resolve(ed25519DID); err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+		}
+		if calls := base.calls.Load(); calls != 2 {
+			t.Errorf("Expected the entry to expire after a minute, got %d base resolutions", calls)
+		}
+	})
+
+	t.Run("cached documents are not shared", func(t *testing.T) {
+		resolver := NewCachingResolver(NewResolver(), 2, 0)
+
+		doc, err := resolver.Resolve(ed25519DID)
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		doc.ID = "mutated"
+		doc.VerificationMethod[0].ID = "mutated"
+		doc.KeyAgreement[0] = "mutated"
+
+		cached, err := resolver.Resolve(ed25519DID)
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		cached.Authentication[0] = "mutated"
+
+		expected, _ := ResolveDocument(ed25519DID)
+		if again, _ := resolver.Resolve(ed25519DID); !again.Equal(expected) {
+			t.Errorf("Expected the cached document to be unchanged, got %+v", again)
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		base := &countingResolver{}
+		resolver := NewCachingResolver(base, 2, 0)
+
+		for range 2 {
+			var resolutionErr *ResolutionError
+			if _, err := resolver.Resolve("did:web:example.com"); !errors.As(err, &resolutionErr) {
+				t.Errorf("Expected *ResolutionError, got %v", err)
+			}
+		}
+		if calls := base.calls.Load(); calls != 2 {
+			t.Errorf("Expected 2 base resolutions, got %d", calls)
+		}
+		if n := resolver.Len(); n != 0 {
+			t.Errorf("Expected no cached documents, got %d", n)
+		}
+	})
+
+	t.Run("concurrent use", func(t *testing.T) {
+		resolver := NewCachingResolver(NewResolver(), 2, 0)
+		dids := []string{ed25519DID, p256DID, secp256k1DID}
+
+		var wg sync.WaitGroup
+		for i := range 16 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range 50 {
+					if _, err := resolver.Resolve(dids[(i+j)%len(dids)]); err != nil {
+						t.Errorf("Resolve failed: %v", err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := resolver.Len(); n > 2 {
+			t.Errorf("Expected at most 2 cached documents, got %d", n)
+		}
+	})
+}
+
+func TestNewCachingResolverCapacity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for zero capacity")
+		}
+	}()
+	NewCachingResolver(NewResolver(), 0, 0)
+}
+
+func BenchmarkCachingResolver(b *testing.B) {
+	did := testVectors["Ed25519-from-spec"].didKey
+
+	b.Run("uncached", func(b *testing.B) {
+		resolver := NewResolver()
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := resolver.Resolve(did); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cache hit", func(b *testing.B) {
+		resolver := NewCachingResolver(NewResolver(), 16, 0)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := resolver.Resolve(did); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
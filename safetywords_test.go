@@ -0,0 +1,94 @@
+package didkey
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSafetyWords(t *testing.T) {
+	dk, err := Parse("did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	words, err := dk.SafetyWords(6)
+	if err != nil {
+		t.Fatalf("SafetyWords failed: %v", err)
+	}
+	// SHA-256 of the multicodec bytes ed01 2e6fcce3...0970e6 starts with f7 87 24 ca 80 52
+	if expected := []string{"virus", "liberty", "bluebird", "revenue", "merit", "enrollment"}; !slices.Equal(words, expected) {
+		t.Fatalf("Expected %v, got %v", expected, words)
+	}
+
+	// The same key always yields the same words, however it was constructed
+	same, err := FromBytes(Ed25519PublicKey, dk.Bytes())
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+	again, err := same.SafetyWords(6)
+	if err != nil {
+		t.Fatalf("SafetyWords failed: %v", err)
+	}
+	if !slices.Equal(words, again) {
+		t.Errorf("Expected %v, got %v", words, again)
+	}
+
+	// Fewer words are a prefix of more
+	all, err := dk.SafetyWords(MaxSafetyWords)
+	if err != nil {
+		t.Fatalf("SafetyWords failed: %v", err)
+	}
+	if !slices.Equal(all[:6], words) {
+		t.Errorf("Expected %v to start with %v", all, words)
+	}
+
+	// Words alternate between the even and odd lists
+	for i, word := range all {
+		list := pgpEvenWords[:]
+		if i%2 == 1 {
+			list = pgpOddWords[:]
+		}
+		if !slices.Contains(list, word) {
+			t.Errorf("Word %d %q is not from the expected list", i, word)
+		}
+	}
+
+	other, err := Parse("did:key:z6MktwupdmLXVVqTzCw4i46r4uGyosGXRnR3XjN4Zq7oMMsw")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	otherWords, err := other.SafetyWords(6)
+	if err != nil {
+		t.Fatalf("SafetyWords failed: %v", err)
+	}
+	if slices.Equal(words, otherWords) {
+		t.Errorf("Expected different keys to yield different words, got %v for both", words)
+	}
+
+	for _, count := range []int{0, -1, MaxSafetyWords + 1} {
+		if _, err := dk.SafetyWords(count); !errors.Is(err, ErrInvalidSafetyWordCount) {
+			t.Errorf("Expected ErrInvalidSafetyWordCount for %d, got %v", count, err)
+		}
+	}
+}
+
+func TestPGPWordLists(t *testing.T) {
+	for name, list := range map[string][256]string{"even": pgpEvenWords, "odd": pgpOddWords} {
+		seen := make(map[string]bool)
+		for _, word := range list {
+			if word == "" || seen[word] {
+				t.Errorf("%s list: empty or duplicate word %q", name, word)
+			}
+			seen[word] = true
+		}
+	}
+
+	// Spot-check both ends of the lists
+	if pgpEvenWords[0x00] != "aardvark" || pgpEvenWords[0xff] != "Zulu" {
+		t.Errorf("Unexpected even list bounds %q, %q", pgpEvenWords[0x00], pgpEvenWords[0xff])
+	}
+	if pgpOddWords[0x00] != "adroitness" || pgpOddWords[0xff] != "Yucatan" {
+		t.Errorf("Unexpected odd list bounds %q, %q", pgpOddWords[0x00], pgpOddWords[0xff])
+	}
+}
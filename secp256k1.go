@@ -0,0 +1,103 @@
+package didkey
+
+import (
+	"math/big"
+)
+
+// secp256k1 group order and generator (SEC 2). The standard library has no
+// secp256k1 support, so ECDSA verification is implemented here with math/big.
+// It is not constant time, which is fine for verification with public inputs.
+var (
+	secp256k1N = hexInt("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	secp256k1G = &ecPoint{
+		x: hexInt("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
+		y: hexInt("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"),
+	}
+)
+
+// ecPoint is an affine curve point; nil is the point at infinity
+type ecPoint struct {
+	x, y *big.Int
+}
+
+// verifySecp256k1 verifies a raw r||s ECDSA signature of a digest by the public
+// key q. Digests longer than 32 bytes are truncated, as in SEC 1. Both low and
+// high s values are accepted, as in JWS ES256K.
+func verifySecp256k1(q *ecPoint, digest, signature []byte) bool {
+	if len(signature) != 64 {
+		return false
+	}
+
+	if len(digest) > 32 {
+		digest = digest[:32]
+	}
+
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return false
+	}
+
+	w := new(big.Int).ModInverse(s, secp256k1N)
+	u1 := new(big.Int).SetBytes(digest)
+	u1.Mul(u1, w).Mod(u1, secp256k1N)
+	u2 := new(big.Int).Mul(r, w)
+	u2.Mod(u2, secp256k1N)
+
+	c := secp256k1Curve
+	point := c.add(c.scalarMult(secp256k1G, u1), c.scalarMult(q, u2))
+	if point == nil {
+		return false
+	}
+
+	return new(big.Int).Mod(point.x, secp256k1N).Cmp(r) == 0
+}
+
+// add returns p1 + p2
+func (c *ecCurve) add(p1, p2 *ecPoint) *ecPoint {
+	if p1 == nil {
+		return p2
+	}
+	if p2 == nil {
+		return p1
+	}
+
+	var lambda *big.Int
+	if p1.x.Cmp(p2.x) == 0 {
+		if sum := new(big.Int).Add(p1.y, p2.y); sum.Mod(sum, c.p).Sign() == 0 {
+			return nil // p2 = -p1
+		}
+
+		// Doubling: λ = (3x² + a) / 2y
+		num := new(big.Int).Mul(p1.x, p1.x)
+		num.Mul(num, big.NewInt(3)).Add(num, c.a)
+		den := new(big.Int).Lsh(p1.y, 1)
+		lambda = num.Mul(num, den.ModInverse(den.Mod(den, c.p), c.p))
+	} else {
+		// λ = (y2 - y1) / (x2 - x1)
+		num := new(big.Int).Sub(p2.y, p1.y)
+		den := new(big.Int).Sub(p2.x, p1.x)
+		lambda = num.Mul(num, den.ModInverse(den.Mod(den, c.p), c.p))
+	}
+	lambda.Mod(lambda, c.p)
+
+	// x3 = λ² - x1 - x2, y3 = λ(x1 - x3) - y1
+	x3 := new(big.Int).Mul(lambda, lambda)
+	x3.Sub(x3, p1.x).Sub(x3, p2.x).Mod(x3, c.p)
+	y3 := new(big.Int).Sub(p1.x, x3)
+	y3.Mul(y3, lambda).Sub(y3, p1.y).Mod(y3, c.p)
+
+	return &ecPoint{x: x3, y: y3}
+}
+
+// scalarMult returns k·p using double-and-add
+func (c *ecCurve) scalarMult(p *ecPoint, k *big.Int) *ecPoint {
+	var result *ecPoint
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = c.add(result, result)
+		if k.Bit(i) == 1 {
+			result = c.add(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package didkey
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+)
+
+// FromSeed deterministically derives a key pair of the given type from seed
+// and returns its DID key and private key, so tests get stable, reproducible
+// DID keys. The key material is derived with HKDF-SHA256 from the seed and the
+// key type, so one seed yields unrelated keys for different key types.
+//
+// Supported key types and their private keys:
+//   - Ed25519: ed25519.PrivateKey
+//   - X25519: *ecdh.PrivateKey
+//   - P-256, P-384: *ecdsa.PrivateKey
+//   - secp256k1: *big.Int, the private scalar, as the standard library has no
+//     secp256k1 key type
+//
+// FromSeed is meant for test fixtures, not for production key generation: keys
+// are only as secret as the seed, and test seeds are usually not secret at all.
+// The secp256k1 public key is computed with the package's math/big curve
+// arithmetic, which is not constant time.
+func FromSeed(keyType KeyType, seed []byte) (*DIDKey, crypto.PrivateKey, error) {
+	if len(seed) == 0 {
+		return nil, nil, ErrEmptySeed
+	}
+
+	switch keyType {
+	case Ed25519PublicKey:
+		key := ed25519.NewKeyFromSeed(deriveSeedBytes(keyType, seed, 0, ed25519.SeedSize))
+		dk, err := FromBytes(keyType, key.Public().(ed25519.PublicKey))
+		return dk, key, err
+	case X25519PublicKey:
+		key, err := ecdh.X25519().NewPrivateKey(deriveSeedBytes(keyType, seed, 0, 32))
+		if err != nil {
+			return nil, nil, err
+		}
+		dk, err := FromBytes(keyType, key.PublicKey().Bytes())
+		return dk, key, err
+	case P256PublicKey:
+		return ecdsaFromSeed(keyType, seed, ecdh.P256(), elliptic.P256())
+	case P384PublicKey:
+		return ecdsaFromSeed(keyType, seed, ecdh.P384(), elliptic.P384())
+	case Secp256k1PublicKey:
+		return secp256k1FromSeed(seed)
+	default:
+		return nil, nil, ErrUnsupportedKeyTypeWithContext(keyType)
+	}
+}
+
+// ecdsaFromSeed derives an ECDSA key by rejection sampling: candidate scalars
+// outside [1, n-1] are rejected by ecdh and the next counter is tried
+func ecdsaFromSeed(keyType KeyType, seed []byte, ecdhCurve ecdh.Curve, curve elliptic.Curve) (*DIDKey, crypto.PrivateKey, error) {
+	size := (curve.Params().BitSize + 7) / 8
+	for counter := 0; ; counter++ {
+		key, err := ecdhCurve.NewPrivateKey(deriveSeedBytes(keyType, seed, counter, size))
+		if err != nil {
+			continue
+		}
+
+		// The ecdh public key is the uncompressed point 0x04 || X || Y
+		point := key.PublicKey().Bytes()
+		privateKey := &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: curve,
+				X:     new(big.Int).SetBytes(point[1 : 1+size]),
+				Y:     new(big.Int).SetBytes(point[1+size:]),
+			},
+			D: new(big.Int).SetBytes(key.Bytes()),
+		}
+
+		ecCurve, _ := curveFor(keyType)
+		dk, err := FromBytes(keyType, ecCurve.compress(privateKey.X, privateKey.Y))
+		return dk, privateKey, err
+	}
+}
+
+// secp256k1FromSeed derives a secp256k1 scalar by rejection sampling like
+// ecdsaFromSeed: candidates outside [1, n-1] are rejected and the next counter
+// is tried
+func secp256k1FromSeed(seed []byte) (*DIDKey, crypto.PrivateKey, error) {
+	for counter := 0; ; counter++ {
+		d := new(big.Int).SetBytes(deriveSeedBytes(Secp256k1PublicKey, seed, counter, secp256k1Curve.size))
+		if d.Sign() == 0 || d.Cmp(secp256k1N) >= 0 {
+			continue
+		}
+
+		point := secp256k1Curve.scalarMult(secp256k1G, d)
+		dk, err := FromBytes(Secp256k1PublicKey, secp256k1Curve.compress(point.x, point.y))
+		return dk, d, err
+	}
+}
+
+// deriveSeedBytes derives length bytes of key material for a key type from seed
+func deriveSeedBytes(keyType KeyType, seed []byte, counter, length int) []byte {
+	info := fmt.Sprintf("did:key FromSeed %s %d", keyType, counter)
+	// hkdf.Key only fails for lengths above 255 hash sizes
+	out, _ := hkdf.Key(sha256.New, seed, nil, info, length)
+	return out
+}
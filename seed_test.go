@@ -0,0 +1,113 @@
+package didkey
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestFromSeed(t *testing.T) {
+	seed := []byte("fixture seed")
+	keyTypes := []KeyType{Ed25519PublicKey, X25519PublicKey, P256PublicKey, P384PublicKey, Secp256k1PublicKey}
+
+	seen := make(map[string]bool)
+	for _, keyType := range keyTypes {
+		t.Run(KeyTypeName(keyType), func(t *testing.T) {
+			dk, priv, err := FromSeed(keyType, seed)
+			if err != nil {
+				t.Fatalf("FromSeed failed: %v", err)
+			}
+			if dk.KeyType() != keyType {
+				t.Errorf("Expected key type %s, got %s", keyType, dk.KeyType())
+			}
+
+			again, _, err := FromSeed(keyType, seed)
+			if err != nil {
+				t.Fatalf("FromSeed failed: %v", err)
+			}
+			if again.String() != dk.String() {
+				t.Errorf("Expected the same DID key for the same seed, got %s and %s", dk, again)
+			}
+
+			other, _, err := FromSeed(keyType, []byte("other seed"))
+			if err != nil {
+				t.Fatalf("FromSeed failed: %v", err)
+			}
+			if other.String() == dk.String() {
+				t.Errorf("Expected different DID keys for different seeds")
+			}
+
+			// The private key matches the DID key
+			switch key := priv.(type) {
+			case *ecdh.PrivateKey:
+				if !bytes.Equal(key.PublicKey().Bytes(), dk.Bytes()) {
+					t.Errorf("X25519 private key does not match the DID key")
+				}
+			case *big.Int:
+				if key.Sign() <= 0 || key.Cmp(secp256k1N) >= 0 {
+					t.Fatalf("Expected a scalar in [1, n-1], got %x", key)
+				}
+				point := secp256k1Curve.scalarMult(secp256k1G, key)
+				if !bytes.Equal(secp256k1Curve.compress(point.x, point.y), dk.Bytes()) {
+					t.Errorf("secp256k1 private key does not match the DID key")
+				}
+			default:
+				signature, didKey, err := Sign(priv, []byte("message"))
+				if err != nil {
+					t.Fatalf("Sign failed: %v", err)
+				}
+				if didKey != dk.String() {
+					t.Errorf("Expected signer %s, got %s", dk, didKey)
+				}
+				if valid, err := dk.Verify([]byte("message"), signature); err != nil || !valid {
+					t.Errorf("Expected valid signature, got %v, %v", valid, err)
+				}
+			}
+
+			seen[dk.Fingerprint()] = true
+		})
+	}
+
+	if len(seen) != len(keyTypes) {
+		t.Errorf("Expected unrelated keys for each key type")
+	}
+}
+
+func TestFromSeedStable(t *testing.T) {
+	// Fixtures rely on the derivation never changing
+	dk, priv, err := FromSeed(Ed25519PublicKey, []byte("fixture seed"))
+	if err != nil {
+		t.Fatalf("FromSeed failed: %v", err)
+	}
+
+	expected := "did:key:z6Mkt4XNE4QSsnf1WNhG41NH6guLfkgzk6ghotgGBLVZ1xjG"
+	if dk.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, dk)
+	}
+	if _, ok := priv.(ed25519.PrivateKey); !ok {
+		t.Errorf("Expected ed25519.PrivateKey, got %T", priv)
+	}
+}
+
+func TestFromSeedErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		keyType     KeyType
+		seed        []byte
+		expectedErr error
+	}{
+		{name: "empty seed", keyType: Ed25519PublicKey, seed: nil, expectedErr: ErrEmptySeed},
+		{name: "BLS12-381 G2", keyType: Bls12381G2PublicKey, seed: []byte("seed"), expectedErr: ErrUnsupportedKeyType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := FromSeed(tt.keyType, tt.seed); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
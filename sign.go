@@ -0,0 +1,145 @@
+package didkey
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+)
+
+// Sign signs message with a private key and returns the signature together
+// with the DID key of the matching public key, in the formats accepted by
+// Verify:
+//   - ed25519.PrivateKey: 64-byte RFC 8032 signature
+//   - *ecdsa.PrivateKey on P-256 or P-384: raw r||s ECDSA signature over the
+//     SHA-256 or SHA-384 digest of message
+//
+// X25519 key agreement keys cannot sign and return ErrKeyAgreementKeyNotSignable.
+// Other private key types, including other curves, return ErrUnsupportedPrivateKey.
+func Sign(priv crypto.PrivateKey, message []byte) (signature []byte, didKey string, err error) {
+	switch key := priv.(type) {
+	case ed25519.PrivateKey:
+		if len(key) != ed25519.PrivateKeySize {
+			return nil, "", ErrInvalidKeySizeWithContext(Ed25519PublicKey, ed25519.PrivateKeySize, len(key))
+		}
+		didKey, err := Encode(Ed25519PublicKey, key.Public().(ed25519.PublicKey))
+		if err != nil {
+			return nil, "", err
+		}
+		return ed25519.Sign(key, message), didKey, nil
+	case *ecdsa.PrivateKey:
+		return signECDSA(key, message)
+	case *ecdh.PrivateKey:
+		if key.Curve() == ecdh.X25519() {
+			return nil, "", requireSignatureKey(X25519PublicKey)
+		}
+		return nil, "", ErrUnsupportedPrivateKeyWithContext(priv)
+	default:
+		return nil, "", ErrUnsupportedPrivateKeyWithContext(priv)
+	}
+}
+
+func signECDSA(key *ecdsa.PrivateKey, message []byte) ([]byte, string, error) {
+	var keyType KeyType
+	var digest []byte
+	switch key.Curve {
+	case elliptic.P256():
+		keyType = P256PublicKey
+		sum := sha256.Sum256(message)
+		digest = sum[:]
+	case elliptic.P384():
+		keyType = P384PublicKey
+		sum := sha512.Sum384(message)
+		digest = sum[:]
+	default:
+		return nil, "", ErrUnsupportedPrivateKeyWithContext(key)
+	}
+
+	curve, _ := curveFor(keyType)
+	didKey, err := Encode(keyType, curve.compress(key.X, key.Y))
+	if err != nil {
+		return nil, "", err
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return nil, "", err
+	}
+
+	signature := make([]byte, 2*curve.size)
+	r.FillBytes(signature[:curve.size])
+	s.FillBytes(signature[curve.size:])
+	return signature, didKey, nil
+}
+
+// FromSigner returns the DID key of the public key of a crypto.Signer, such as
+// a key held by an HSM or cloud KMS, so the raw public key need not be exported:
+//   - ed25519.PublicKey
+//   - *ecdsa.PublicKey on P-256 or P-384, compressed before encoding
+//   - *ecdh.PublicKey on X25519, P-256 or P-384
+//
+// Other public key types, including other curves, return ErrUnsupportedKeyType.
+func FromSigner(signer crypto.Signer, opts ...Option) (*DIDKey, error) {
+	return fromPublicKey(signer.Public(), opts)
+}
+
+// fromPublicKey returns the DID key of a crypto.PublicKey of the types listed
+// by FromSigner
+func fromPublicKey(pub crypto.PublicKey, opts []Option) (*DIDKey, error) {
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		return FromBytes(Ed25519PublicKey, pub, opts...)
+	case *ecdsa.PublicKey:
+		return fromECDSAPublicKey(pub, opts)
+	case *ecdh.PublicKey:
+		return fromECDHPublicKey(pub, opts)
+	default:
+		return nil, ErrUnsupportedPublicKeyWithContext(pub)
+	}
+}
+
+func fromECDSAPublicKey(pub *ecdsa.PublicKey, opts []Option) (*DIDKey, error) {
+	var keyType KeyType
+	switch pub.Curve {
+	case elliptic.P256():
+		keyType = P256PublicKey
+	case elliptic.P384():
+		keyType = P384PublicKey
+	default:
+		return nil, ErrUnsupportedPublicKeyWithContext(pub)
+	}
+
+	if pub.X == nil || pub.Y == nil {
+		return nil, ErrInvalidPointWithContext(keyType)
+	}
+
+	// FromBytes checks that the compressed point is on the curve
+	curve, _ := curveFor(keyType)
+	return FromBytes(keyType, curve.compress(pub.X, pub.Y), opts...)
+}
+
+func fromECDHPublicKey(pub *ecdh.PublicKey, opts []Option) (*DIDKey, error) {
+	var keyType KeyType
+	switch pub.Curve() {
+	case ecdh.X25519():
+		return FromBytes(X25519PublicKey, pub.Bytes(), opts...)
+	case ecdh.P256():
+		keyType = P256PublicKey
+	case ecdh.P384():
+		keyType = P384PublicKey
+	default:
+		return nil, ErrUnsupportedPublicKeyWithContext(pub)
+	}
+
+	// NIST curve ecdh public keys are the uncompressed point 0x04 || X || Y
+	curve, _ := curveFor(keyType)
+	keyBytes, err := curve.compressUncompressed(keyType, pub.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return FromBytes(keyType, keyBytes, opts...)
+}
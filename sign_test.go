@@ -0,0 +1,168 @@
+package didkey
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestSignRoundTrip(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		priv    crypto.PrivateKey
+		keyType KeyType
+	}{
+		{name: "Ed25519", priv: ed25519Key, keyType: Ed25519PublicKey},
+		{name: "P-256", priv: p256Key, keyType: P256PublicKey},
+		{name: "P-384", priv: p384Key, keyType: P384PublicKey},
+	}
+
+	message := []byte("hello did:key")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signature, didKey, err := Sign(tt.priv, message)
+			if err != nil {
+				t.Fatalf("Sign failed: %v", err)
+			}
+
+			dk, err := Parse(didKey)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if dk.KeyType() != tt.keyType {
+				t.Errorf("Expected key type %s, got %s", tt.keyType, dk.KeyType())
+			}
+
+			if valid, err := dk.Verify(message, signature); err != nil || !valid {
+				t.Errorf("Expected valid signature, got %v, %v", valid, err)
+			}
+			if valid, err := dk.Verify([]byte("tampered"), signature); err != nil || valid {
+				t.Errorf("Expected invalid signature, got %v, %v", valid, err)
+			}
+		})
+	}
+}
+
+func TestSignUnsupported(t *testing.T) {
+	p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	x25519Key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		priv        crypto.PrivateKey
+		expectedErr error
+	}{
+		{name: "P-224", priv: p224Key, expectedErr: ErrUnsupportedPrivateKey},
+		{name: "X25519", priv: x25519Key, expectedErr: ErrKeyAgreementKeyNotSignable},
+		{name: "nil", priv: nil, expectedErr: ErrUnsupportedPrivateKey},
+		{name: "short Ed25519", priv: ed25519.PrivateKey(make([]byte, 32)), expectedErr: ErrInvalidKeySize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Sign(tt.priv, []byte("message")); !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+// mockSigner is a crypto.Signer exposing only its public key, like an HSM or
+// KMS key
+type mockSigner struct {
+	public crypto.PublicKey
+}
+
+func (s mockSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s mockSigner) Sign(io.Reader, []byte, crypto.SignerOpts) ([]byte, error) {
+	return nil, errors.New("mockSigner cannot sign")
+}
+
+func TestFromSigner(t *testing.T) {
+	tv := testVectors["Ed25519-test-1"]
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	p256ECDH, err := p256Key.PublicKey.ECDH()
+	if err != nil {
+		t.Fatalf("ECDH failed: %v", err)
+	}
+	p256DID, err := EncodeECCoordinates(P256PublicKey, p256Key.X.FillBytes(make([]byte, 32)), p256Key.Y.FillBytes(make([]byte, 32)))
+	if err != nil {
+		t.Fatalf("EncodeECCoordinates failed: %v", err)
+	}
+	x25519Key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	x25519DID, err := Encode(X25519PublicKey, x25519Key.PublicKey().Bytes())
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	p224Key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		public      crypto.PublicKey
+		expected    string
+		expectedErr error
+	}{
+		{name: "Ed25519", public: ed25519.PublicKey(mustDecodeHex(tv.keyHex)), expected: tv.didKey},
+		{name: "ECDSA P-256", public: &p256Key.PublicKey, expected: p256DID},
+		{name: "ECDH P-256", public: p256ECDH, expected: p256DID},
+		{name: "X25519", public: x25519Key.PublicKey(), expected: x25519DID},
+		{name: "short Ed25519", public: ed25519.PublicKey(make([]byte, 31)), expectedErr: ErrInvalidKeySize},
+		{name: "P-224", public: &p224Key.PublicKey, expectedErr: ErrUnsupportedKeyType},
+		{name: "unknown type", public: struct{}{}, expectedErr: ErrUnsupportedKeyType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dk, err := FromSigner(mockSigner{public: tt.public})
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if dk.String() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, dk)
+			}
+		})
+	}
+}
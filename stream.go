@@ -0,0 +1,68 @@
+package didkey
+
+import (
+	"io"
+
+	"github.com/multiformats/go-varint"
+)
+
+// maxFrameSize bounds the payload length accepted by ReadDIDKey. The largest
+// supported key (BLS12-381 G2, 96 bytes) plus its multicodec prefix is far
+// below it, so anything larger is a corrupt or hostile stream.
+const maxFrameSize = 256
+
+// WriteDIDKey writes a DID key to w as a single binary frame:
+//
+//	UVARINT(len(payload)) || payload
+//
+// where payload is the multicodec-prefixed key bytes (see MulticodecBytes).
+// This avoids the base58 overhead of the string form on the wire.
+func WriteDIDKey(w io.Writer, dk *DIDKey) error {
+	payload := dk.MulticodecBytes()
+	frame := append(varint.ToUvarint(uint64(len(payload))), payload...)
+
+	_, err := w.Write(frame)
+	return err
+}
+
+// ReadDIDKey reads one DID key frame written by WriteDIDKey from r and
+// validates the key. It returns io.EOF if r is exhausted before a frame
+// starts and io.ErrUnexpectedEOF if it ends within one. It never reads past
+// the end of the frame.
+func ReadDIDKey(r io.Reader, opts ...Option) (*DIDKey, error) {
+	length, err := varint.ReadUvarint(&singleByteReader{r: r})
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, ErrInvalidVarintWithContext(err)
+	}
+
+	if length > maxFrameSize {
+		return nil, ErrFrameTooLargeWithContext(length, maxFrameSize)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	return FromMulticodecBytes(payload, opts...)
+}
+
+// singleByteReader adapts an io.Reader to an io.ByteReader without buffering,
+// so reading a frame header never consumes bytes of the following frame
+type singleByteReader struct {
+	r   io.Reader
+	buf [1]byte
+}
+
+func (b *singleByteReader) ReadByte() (byte, error) {
+	if _, err := io.ReadFull(b.r, b.buf[:]); err != nil {
+		return 0, err
+	}
+	return b.buf[0], nil
+}
@@ -0,0 +1,88 @@
+package didkey
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadWriteDIDKey(t *testing.T) {
+	var didKeys []string
+	for _, tv := range testVectors {
+		didKeys = append(didKeys, tv.didKey)
+	}
+	blsDID, err := Encode(Bls12381G2PublicKey, bytes.Repeat([]byte{0xa5}, 96))
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	didKeys = append(didKeys, blsDID)
+
+	var buf bytes.Buffer
+	for _, didKey := range didKeys {
+		dk, err := Parse(didKey)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		if err := WriteDIDKey(&buf, dk); err != nil {
+			t.Fatalf("WriteDIDKey failed: %v", err)
+		}
+	}
+
+	// Reading one byte at a time must not lose frame boundaries
+	r := iotest.OneByteReader(&buf)
+	for i, didKey := range didKeys {
+		dk, err := ReadDIDKey(r)
+		if err != nil {
+			t.Fatalf("ReadDIDKey %d failed: %v", i, err)
+		}
+
+		if dk.String() != didKey {
+			t.Errorf("Frame %d: expected %s, got %s", i, didKey, dk.String())
+		}
+	}
+
+	if _, err := ReadDIDKey(r); err != io.EOF {
+		t.Errorf("Expected io.EOF after the last frame, got %v", err)
+	}
+}
+
+func TestReadDIDKeyErrors(t *testing.T) {
+	dk, err := Parse(testVectors["Ed25519-from-spec"].didKey)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDIDKey(&buf, dk); err != nil {
+		t.Fatalf("WriteDIDKey failed: %v", err)
+	}
+	frame := buf.Bytes()
+
+	t.Run("truncated payload", func(t *testing.T) {
+		if _, err := ReadDIDKey(bytes.NewReader(frame[:len(frame)-1])); err != io.ErrUnexpectedEOF {
+			t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+
+	t.Run("truncated length", func(t *testing.T) {
+		if _, err := ReadDIDKey(bytes.NewReader([]byte{0x80})); err != io.ErrUnexpectedEOF {
+			t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+		}
+	})
+
+	t.Run("frame too large", func(t *testing.T) {
+		if _, err := ReadDIDKey(bytes.NewReader([]byte{0xff, 0xff, 0x03})); !errors.Is(err, ErrFrameTooLarge) {
+			t.Errorf("Expected ErrFrameTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("wrong key size", func(t *testing.T) {
+		// Length 3: Ed25519 code followed by a single key byte
+		if _, err := ReadDIDKey(bytes.NewReader([]byte{0x03, 0xed, 0x01, 0x00})); !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected ErrInvalidKeySize, got %v", err)
+		}
+	})
+}
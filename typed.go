@@ -0,0 +1,81 @@
+package didkey
+
+// KeyConstraint is implemented by the marker types that fix the key type of a
+// TypedKey, such as Ed25519Key
+type KeyConstraint interface {
+	KeyType() KeyType
+}
+
+// Marker types for TypedKey, one per supported key type
+type (
+	Ed25519Key    struct{}
+	X25519Key     struct{}
+	Secp256k1Key  struct{}
+	Bls12381G1Key struct{}
+	Bls12381G2Key struct{}
+	P256Key       struct{}
+	P384Key       struct{}
+)
+
+func (Ed25519Key) KeyType() KeyType    { return Ed25519PublicKey }
+func (X25519Key) KeyType() KeyType     { return X25519PublicKey }
+func (Secp256k1Key) KeyType() KeyType  { return Secp256k1PublicKey }
+func (Bls12381G1Key) KeyType() KeyType { return Bls12381G1PublicKey }
+func (Bls12381G2Key) KeyType() KeyType { return Bls12381G2PublicKey }
+func (P256Key) KeyType() KeyType       { return P256PublicKey }
+func (P384Key) KeyType() KeyType       { return P384PublicKey }
+
+// TypedKey is a DIDKey whose key type is known at compile time, so that a
+// function can require e.g. an Ed25519DIDKey and reject an X25519 key at the
+// call site. It must be constructed with NewTypedKey or ParseTyped; the methods
+// of a zero-value TypedKey panic.
+type TypedKey[K KeyConstraint] struct {
+	key *DIDKey
+}
+
+// Typed DID keys for each supported key type
+type (
+	Ed25519DIDKey    = TypedKey[Ed25519Key]
+	X25519DIDKey     = TypedKey[X25519Key]
+	Secp256k1DIDKey  = TypedKey[Secp256k1Key]
+	Bls12381G1DIDKey = TypedKey[Bls12381G1Key]
+	Bls12381G2DIDKey = TypedKey[Bls12381G2Key]
+	P256DIDKey       = TypedKey[P256Key]
+	P384DIDKey       = TypedKey[P384Key]
+)
+
+// NewTypedKey wraps a DIDKey, failing with ErrKeyTypeMismatch if it is not of
+// the key type selected by K, or with ErrNilDIDKey if it is nil
+func NewTypedKey[K KeyConstraint](dk *DIDKey) (*TypedKey[K], error) {
+	if dk == nil {
+		return nil, ErrNilDIDKey
+	}
+
+	var k K
+	if dk.keyType != k.KeyType() {
+		return nil, ErrKeyTypeMismatchWithContext(k.KeyType(), dk.keyType)
+	}
+
+	return &TypedKey[K]{key: dk}, nil
+}
+
+// ParseTyped decodes a DID key string that must be of the key type selected by K,
+// e.g. ParseTyped[Ed25519Key](didKey)
+func ParseTyped[K KeyConstraint](didKey string, opts ...Option) (*TypedKey[K], error) {
+	dk, err := Parse(didKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTypedKey[K](dk)
+}
+
+// DIDKey returns the underlying DIDKey
+func (tk *TypedKey[K]) DIDKey() *DIDKey {
+	return tk.key
+}
+
+// String returns the DID key string, e.g. did:key:z6Mk...
+func (tk *TypedKey[K]) String() string {
+	return tk.key.String()
+}
@@ -0,0 +1,81 @@
+package didkey
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTypedKey(t *testing.T) {
+	ed25519DID := testVectors["Ed25519-from-spec"].didKey
+	p256DID := testVectors["P-256-test"].didKey
+
+	t.Run("matching type", func(t *testing.T) {
+		tk, err := ParseTyped[Ed25519Key](ed25519DID)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// A function can require an Ed25519 key in its signature
+		requireEd25519 := func(key *Ed25519DIDKey) string { return key.String() }
+		if requireEd25519(tk) != ed25519DID {
+			t.Errorf("Expected %s, got %s", ed25519DID, tk.String())
+		}
+
+		if tk.DIDKey().KeyType() != Ed25519PublicKey {
+			t.Errorf("Expected Ed25519 key, got %s", tk.DIDKey().KeyType())
+		}
+	})
+
+	t.Run("P-256", func(t *testing.T) {
+		dk, err := Parse(p256DID)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		tk, err := NewTypedKey[P256Key](dk)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var _ *P256DIDKey = tk
+		if tk.DIDKey() != dk {
+			t.Errorf("Expected the wrapped DIDKey to be returned")
+		}
+	})
+
+	t.Run("nil DIDKey", func(t *testing.T) {
+		if _, err := NewTypedKey[Ed25519Key](nil); !errors.Is(err, ErrNilDIDKey) {
+			t.Errorf("Expected ErrNilDIDKey, got %v", err)
+		}
+	})
+
+	t.Run("mismatched type", func(t *testing.T) {
+		if _, err := ParseTyped[Ed25519Key]("did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p"); !errors.Is(err, ErrKeyTypeMismatch) {
+			t.Errorf("Expected ErrKeyTypeMismatch, got %v", err)
+		}
+
+		dk, err := Parse(ed25519DID)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		if _, err := NewTypedKey[P256Key](dk); !errors.Is(err, ErrKeyTypeMismatch) {
+			t.Errorf("Expected ErrKeyTypeMismatch, got %v", err)
+		}
+	})
+
+	t.Run("invalid DID key", func(t *testing.T) {
+		if _, err := ParseTyped[Ed25519Key]("did:web:example.com"); !errors.Is(err, ErrInvalidDIDKeyPrefix) {
+			t.Errorf("Expected ErrInvalidDIDKeyPrefix, got %v", err)
+		}
+	})
+
+	t.Run("markers cover every key type", func(t *testing.T) {
+		markers := []KeyConstraint{Ed25519Key{}, X25519Key{}, Secp256k1Key{}, Bls12381G1Key{}, Bls12381G2Key{}, P256Key{}, P384Key{}}
+		for i, keyType := range supportedKeyTypes {
+			if markers[i].KeyType() != keyType {
+				t.Errorf("Expected marker %d to select %s, got %s", i, keyType, markers[i].KeyType())
+			}
+		}
+	})
+}
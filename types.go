@@ -1,6 +1,9 @@
 package didkey
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/multiformats/go-multicodec"
 )
 
@@ -17,26 +20,228 @@ const (
 	P384PublicKey       KeyType = multicodec.P384Pub
 )
 
+// keyTypeMeta is the static metadata of a supported key type
+type keyTypeMeta struct {
+	keyType       KeyType
+	name          string   // Human-friendly name returned by KeyTypeName
+	size          int      // Size in bytes of encoded keys
+	relationships []string // Verification relationships in resolved DID Documents
+}
+
+var signingRelationships = []string{Authentication, AssertionMethod, CapabilityDelegation, CapabilityInvocation}
+
+// keyTypeTable lists the metadata of every key type accepted by Encode and
+// Decode. It is the single place to add a key type.
+var keyTypeTable = []keyTypeMeta{
+	{
+		keyType: Ed25519PublicKey,
+		name:    "Ed25519",
+		size:    32,
+		// keyAgreement is served by the derived X25519 key
+		relationships: append(slices.Clip(signingRelationships), KeyAgreement),
+	},
+	{keyType: X25519PublicKey, name: "X25519", size: 32, relationships: []string{KeyAgreement}},
+	{keyType: Secp256k1PublicKey, name: "secp256k1", size: 33, relationships: signingRelationships}, // Compressed format
+	{keyType: Bls12381G1PublicKey, name: "BLS12-381 G1", size: 48, relationships: signingRelationships},
+	{keyType: Bls12381G2PublicKey, name: "BLS12-381 G2", size: 96, relationships: signingRelationships},
+	{keyType: P256PublicKey, name: "P-256", size: 33, relationships: signingRelationships}, // Compressed format
+	{keyType: P384PublicKey, name: "P-384", size: 49, relationships: signingRelationships}, // Compressed format
+}
+
+// keyTypeMetadata indexes keyTypeTable by multicodec code for the hot decode path.
+// It is built at init and never written afterwards, so lookups take no lock;
+// the only runtime registry, of RegisterCurveOps, is guarded by curveOpsMu.
+var keyTypeMetadata = func() map[uint64]keyTypeMeta {
+	metadata := make(map[uint64]keyTypeMeta, len(keyTypeTable))
+	for _, meta := range keyTypeTable {
+		metadata[uint64(meta.keyType)] = meta
+	}
+	return metadata
+}()
+
+// supportedKeyTypes lists every key type accepted by Encode and Decode
+var supportedKeyTypes = func() []KeyType {
+	keyTypes := make([]KeyType, len(keyTypeTable))
+	for i, meta := range keyTypeTable {
+		keyTypes[i] = meta.keyType
+	}
+	return keyTypes
+}()
+
+// KeyTypeName returns the human-friendly name of a key type, e.g. "Ed25519" or
+// "P-256". Unsupported key types fall back to their multicodec name.
+func KeyTypeName(keyType KeyType) string {
+	if meta, ok := keyTypeMetadata[uint64(keyType)]; ok {
+		return meta.name
+	}
+	return keyType.String()
+}
+
+// keyTypeAliases maps lowercase spellings of key types that ParseKeyType
+// accepts in addition to the multicodec and KeyTypeName names
+var keyTypeAliases = map[string]KeyType{
+	"ed25519-pub":      Ed25519PublicKey,
+	"x25519-pub":       X25519PublicKey,
+	"secp256k1-pub":    Secp256k1PublicKey,
+	"k256":             Secp256k1PublicKey,
+	"k-256":            Secp256k1PublicKey,
+	"bls12_381-g1-pub": Bls12381G1PublicKey,
+	"bls12-381-g1":     Bls12381G1PublicKey,
+	"bls12381g1":       Bls12381G1PublicKey,
+	"bls12_381-g2-pub": Bls12381G2PublicKey,
+	"bls12-381-g2":     Bls12381G2PublicKey,
+	"bls12381g2":       Bls12381G2PublicKey,
+	"p256-pub":         P256PublicKey,
+	"p256":             P256PublicKey,
+	"p384-pub":         P384PublicKey,
+	"p384":             P384PublicKey,
+
+	// OpenSSL/TLS curve names
+	"prime256v1": P256PublicKey,
+	"secp256r1":  P256PublicKey,
+	"secp384r1":  P384PublicKey,
+}
+
+// ParseKeyType parses a key type from its multicodec name (e.g. "ed25519-pub"),
+// its KeyTypeName (e.g. "Ed25519") or a common alias (e.g. "p256", or the
+// OpenSSL names "prime256v1", "secp256r1" and "secp384r1"). Matching is
+// case-insensitive and ignores surrounding whitespace.
+func ParseKeyType(s string) (KeyType, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+
+	if keyType, ok := keyTypeAliases[name]; ok {
+		return keyType, nil
+	}
+
+	for _, keyType := range supportedKeyTypes {
+		if strings.ToLower(KeyTypeName(keyType)) == name {
+			return keyType, nil
+		}
+	}
+
+	return 0, ErrUnknownKeyTypeNameWithContext(s)
+}
+
+// normalizeKeyBytes converts accepted alternative key encodings to the form
+// stored in DID keys:
+//   - a 65-byte uncompressed secp256k1 key (0x04 || X || Y), as produced by
+//     most Bitcoin and Ethereum libraries, is validated and compressed
+//   - an X9.62 hybrid EC key (0x06/0x07 || X || Y), as produced by some legacy
+//     encoders, is validated and compressed
+//   - with WithLeftPad, a short Ed25519 key is left-padded with zero bytes
+//
+// All other keys are returned unchanged.
+func normalizeKeyBytes(keyType KeyType, keyBytes []byte, o options) ([]byte, error) {
+	curve, isEC := curveFor(keyType)
+
+	switch {
+	case isEC && curve.isHybrid(keyBytes):
+		return curve.compressHybrid(keyType, keyBytes)
+	case keyType == Secp256k1PublicKey && len(keyBytes) == 65:
+		return secp256k1Curve.compressUncompressed(keyType, keyBytes)
+	case keyType == Ed25519PublicKey && o.leftPad && len(keyBytes) < 32:
+		padded := make([]byte, 32)
+		copy(padded[32-len(keyBytes):], keyBytes)
+		return padded, nil
+	default:
+		return keyBytes, nil
+	}
+}
+
+// validateKey validates the key bytes for the given key type, including the
+// compressed point prefix of EC keys and their on-curve check unless it is
+// disabled, and in strict mode the order of Ed25519 keys and all-zero keys
+func validateKey(keyType KeyType, keyBytes []byte, o options) error {
+	if err := validateKeySize(keyType, keyBytes); err != nil {
+		if isBLSKeyType(keyType) && isUncompressedBLS(keyType, keyBytes) {
+			return ErrUncompressedBLSKeyWithContext(keyType, len(keyBytes)/2, len(keyBytes))
+		}
+		return err
+	}
+
+	if err := validateCompressedPrefix(keyType, keyBytes); err != nil {
+		return err
+	}
+
+	if err := validateBLSFlags(keyType, keyBytes); err != nil {
+		return err
+	}
+
+	if o.rejectSmallOrder && keyType == Ed25519PublicKey {
+		if err := validateEd25519Order(keyBytes); err != nil {
+			return err
+		}
+	}
+
+	if o.rejectAllZero {
+		if err := validateNotAllZero(keyType, keyBytes); err != nil {
+			return err
+		}
+	}
+
+	if o.curveValidation {
+		return validatePoint(keyType, keyBytes)
+	}
+
+	return nil
+}
+
+// Flag bits of the leading byte of serialized BLS12-381 points, in the ZCash
+// format used by did:key
+const (
+	blsCompressionFlag = 0x80
+	blsInfinityFlag    = 0x40
+)
+
+func isBLSKeyType(keyType KeyType) bool {
+	return keyType == Bls12381G1PublicKey || keyType == Bls12381G2PublicKey
+}
+
+// isUncompressedBLS reports whether key bytes of a BLS key type look like an
+// uncompressed point: twice the compressed size, without the compression flag
+func isUncompressedBLS(keyType KeyType, keyBytes []byte) bool {
+	size, _ := keySize(keyType)
+	return len(keyBytes) == 2*size && keyBytes[0]&blsCompressionFlag == 0
+}
+
+// validateBLSFlags validates the flag bits of compressed BLS12-381 key bytes:
+// the compression flag must be set and the infinity flag clear, as the point
+// at infinity is not a valid public key. Other key types are accepted as-is.
+func validateBLSFlags(keyType KeyType, keyBytes []byte) error {
+	if !isBLSKeyType(keyType) {
+		return nil
+	}
+
+	if keyBytes[0]&blsCompressionFlag == 0 {
+		return ErrInvalidBLSEncodingWithContext(keyType, "compression flag not set")
+	}
+	if keyBytes[0]&blsInfinityFlag != 0 {
+		return ErrInvalidBLSEncodingWithContext(keyType, "point at infinity")
+	}
+
+	return nil
+}
+
+// validateNotAllZero rejects keys whose bytes after the compressed point
+// prefix, if any, are all zero
+func validateNotAllZero(keyType KeyType, keyBytes []byte) error {
+	if _, ok := curveFor(keyType); ok {
+		keyBytes = keyBytes[1:]
+	}
+
+	for _, b := range keyBytes {
+		if b != 0 {
+			return nil
+		}
+	}
+
+	return ErrAllZeroKeyWithContext(keyType)
+}
+
 // validateKeySize validates that the key bytes have the correct size for the given key type
 func validateKeySize(keyType KeyType, keyBytes []byte) error {
-	var expectedSize int
-
-	switch keyType {
-	case Ed25519PublicKey:
-		expectedSize = 32
-	case X25519PublicKey:
-		expectedSize = 32
-	case Secp256k1PublicKey:
-		expectedSize = 33 // Compressed format
-	case Bls12381G1PublicKey:
-		expectedSize = 48
-	case Bls12381G2PublicKey:
-		expectedSize = 96
-	case P256PublicKey:
-		expectedSize = 33 // Compressed format
-	case P384PublicKey:
-		expectedSize = 49 // Compressed format
-	default:
+	expectedSize, ok := keySize(keyType)
+	if !ok {
 		return ErrUnsupportedKeyTypeWithContext(keyType)
 	}
 
@@ -46,3 +251,21 @@ func validateKeySize(keyType KeyType, keyBytes []byte) error {
 
 	return nil
 }
+
+// keyTypesOfSize returns the key types whose encoded keys have size bytes, in
+// keyTypeTable order
+func keyTypesOfSize(size int) []KeyType {
+	var keyTypes []KeyType
+	for _, meta := range keyTypeTable {
+		if meta.size == size {
+			keyTypes = append(keyTypes, meta.keyType)
+		}
+	}
+	return keyTypes
+}
+
+// keySize returns the size in bytes of encoded keys of a key type
+func keySize(keyType KeyType) (int, bool) {
+	meta, ok := keyTypeMetadata[uint64(keyType)]
+	return meta.size, ok
+}
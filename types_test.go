@@ -0,0 +1,317 @@
+package didkey
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"errors"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/multiformats/go-varint"
+)
+
+func TestKeyTypeName(t *testing.T) {
+	expected := map[KeyType]string{
+		Ed25519PublicKey:    "Ed25519",
+		X25519PublicKey:     "X25519",
+		Secp256k1PublicKey:  "secp256k1",
+		Bls12381G1PublicKey: "BLS12-381 G1",
+		Bls12381G2PublicKey: "BLS12-381 G2",
+		P256PublicKey:       "P-256",
+		P384PublicKey:       "P-384",
+	}
+
+	for keyType, name := range expected {
+		if KeyTypeName(keyType) != name {
+			t.Errorf("Expected %s, got %s", name, KeyTypeName(keyType))
+		}
+	}
+}
+
+func TestParseKeyType(t *testing.T) {
+	tests := map[string]KeyType{
+		// Multicodec names
+		"ed25519-pub":      Ed25519PublicKey,
+		"x25519-pub":       X25519PublicKey,
+		"secp256k1-pub":    Secp256k1PublicKey,
+		"bls12_381-g1-pub": Bls12381G1PublicKey,
+		"bls12_381-g2-pub": Bls12381G2PublicKey,
+		"p256-pub":         P256PublicKey,
+		"p384-pub":         P384PublicKey,
+
+		// Friendly names
+		"Ed25519":      Ed25519PublicKey,
+		"X25519":       X25519PublicKey,
+		"secp256k1":    Secp256k1PublicKey,
+		"BLS12-381 G1": Bls12381G1PublicKey,
+		"BLS12-381 G2": Bls12381G2PublicKey,
+		"P-256":        P256PublicKey,
+		"P-384":        P384PublicKey,
+
+		// Aliases and case variations
+		"ed25519":       Ed25519PublicKey,
+		"ED25519-PUB":   Ed25519PublicKey,
+		"  Ed25519  ":   Ed25519PublicKey,
+		"k256":          Secp256k1PublicKey,
+		"bls12381g2":    Bls12381G2PublicKey,
+		"p256":          P256PublicKey,
+		"p-384":         P384PublicKey,
+		"Secp256k1-Pub": Secp256k1PublicKey,
+
+		// OpenSSL/TLS curve names
+		"prime256v1": P256PublicKey,
+		"secp256r1":  P256PublicKey,
+		"SECP256R1":  P256PublicKey,
+		"secp384r1":  P384PublicKey,
+	}
+
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			keyType, err := ParseKeyType(input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if keyType != expected {
+				t.Errorf("Expected %s, got %s", expected, keyType)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "rsa", "ed448", "sha2-256"} {
+		t.Run("unknown "+input, func(t *testing.T) {
+			if _, err := ParseKeyType(input); !errors.Is(err, ErrUnknownKeyTypeName) {
+				t.Errorf("Expected ErrUnknownKeyTypeName, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKeyTypeTable(t *testing.T) {
+	seen := make(map[KeyType]bool)
+	for _, meta := range keyTypeTable {
+		if seen[meta.keyType] {
+			t.Errorf("Duplicate key type %s", meta.keyType)
+		}
+		seen[meta.keyType] = true
+
+		if size, ok := keySize(meta.keyType); !ok || size != keySizeSwitch(meta.keyType) {
+			t.Errorf("Unexpected size %d for %s", size, meta.keyType)
+		}
+	}
+
+	if _, ok := keySize(KeyType(0x1337)); ok {
+		t.Errorf("Expected no metadata for an unknown key type")
+	}
+
+	// Callers must not be able to modify the shared metadata
+	VerificationRelationships(Secp256k1PublicKey)[0] = "modified"
+	if VerificationRelationships(P256PublicKey)[0] != Authentication {
+		t.Errorf("VerificationRelationships returned shared metadata")
+	}
+}
+
+// TestKeySizesMatchGeneratedKeys cross-checks the declared size of every key
+// type against a freshly generated public key of that type, so that a
+// transposed or mistyped size in keyTypeTable is caught
+func TestKeySizesMatchGeneratedKeys(t *testing.T) {
+	randomScalar := func(t *testing.T, n *big.Int) *big.Int {
+		k, err := cryptorand.Int(cryptorand.Reader, new(big.Int).Sub(n, big.NewInt(1)))
+		if err != nil {
+			t.Fatalf("Failed to generate scalar: %v", err)
+		}
+		return k.Add(k, big.NewInt(1))
+	}
+	ecdsaKey := func(t *testing.T, curve elliptic.Curve) []byte {
+		key, err := ecdsa.GenerateKey(curve, cryptorand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		return elliptic.MarshalCompressed(curve, key.X, key.Y)
+	}
+
+	generators := map[KeyType]func(t *testing.T) []byte{
+		Ed25519PublicKey: func(t *testing.T) []byte {
+			pub, _, err := ed25519.GenerateKey(cryptorand.Reader)
+			if err != nil {
+				t.Fatalf("Failed to generate key: %v", err)
+			}
+			return pub
+		},
+		X25519PublicKey: func(t *testing.T) []byte {
+			key, err := ecdh.X25519().GenerateKey(cryptorand.Reader)
+			if err != nil {
+				t.Fatalf("Failed to generate key: %v", err)
+			}
+			return key.PublicKey().Bytes()
+		},
+		Secp256k1PublicKey: func(t *testing.T) []byte {
+			point := secp256k1Curve.scalarMult(secp256k1G, randomScalar(t, secp256k1N))
+			return secp256k1Curve.compress(point.x, point.y)
+		},
+		P256PublicKey: func(t *testing.T) []byte { return ecdsaKey(t, elliptic.P256()) },
+		P384PublicKey: func(t *testing.T) []byte { return ecdsaKey(t, elliptic.P384()) },
+		Bls12381G1PublicKey: func(t *testing.T) []byte {
+			_, _, g1, _ := bls12381.Generators()
+			var key bls12381.G1Affine
+			key.ScalarMultiplication(&g1, randomScalar(t, fr.Modulus()))
+			b := key.Bytes()
+			return b[:]
+		},
+		Bls12381G2PublicKey: func(t *testing.T) []byte {
+			_, _, _, g2 := bls12381.Generators()
+			var key bls12381.G2Affine
+			key.ScalarMultiplication(&g2, randomScalar(t, fr.Modulus()))
+			b := key.Bytes()
+			return b[:]
+		},
+	}
+
+	for _, meta := range keyTypeTable {
+		t.Run(meta.name, func(t *testing.T) {
+			generate, ok := generators[meta.keyType]
+			if !ok {
+				t.Fatalf("No key generator for %s", meta.name)
+			}
+
+			keyBytes := generate(t)
+			if size, _ := keySize(meta.keyType); size != len(keyBytes) {
+				t.Errorf("Declared size %d, generated key has %d bytes", size, len(keyBytes))
+			}
+
+			if _, err := FromBytes(meta.keyType, keyBytes); err != nil {
+				t.Errorf("FromBytes rejected a generated key: %v", err)
+			}
+		})
+	}
+}
+
+func TestBLSCompressionFlags(t *testing.T) {
+	_, _, g1, g2 := bls12381.Generators()
+	g1Compressed, g2Compressed := g1.Bytes(), g2.Bytes()
+	g1Uncompressed, g2Uncompressed := g1.RawBytes(), g2.RawBytes()
+	var infinity bls12381.G1Affine
+	infinityCompressed := infinity.Bytes()
+
+	tests := []struct {
+		name        string
+		keyType     KeyType
+		keyBytes    []byte
+		expectedErr error
+	}{
+		{name: "compressed G1 generator", keyType: Bls12381G1PublicKey, keyBytes: g1Compressed[:]},
+		{name: "compressed G2 generator", keyType: Bls12381G2PublicKey, keyBytes: g2Compressed[:]},
+		{name: "compressed G1 key", keyType: Bls12381G1PublicKey, keyBytes: mustDecodeHex("a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a")},
+		{name: "uncompressed G1", keyType: Bls12381G1PublicKey, keyBytes: g1Uncompressed[:], expectedErr: ErrInvalidBLSEncoding},
+		{name: "uncompressed G2", keyType: Bls12381G2PublicKey, keyBytes: g2Uncompressed[:], expectedErr: ErrInvalidBLSEncoding},
+		{name: "compression flag cleared", keyType: Bls12381G1PublicKey, keyBytes: append([]byte{g1Compressed[0] &^ 0x80}, g1Compressed[1:]...), expectedErr: ErrInvalidBLSEncoding},
+		{name: "point at infinity", keyType: Bls12381G1PublicKey, keyBytes: infinityCompressed[:], expectedErr: ErrInvalidBLSEncoding},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Encode(tt.keyType, tt.keyBytes)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected Encode to return %v, got %v", tt.expectedErr, err)
+			}
+
+			// Decode applies the same checks to DID keys encoded elsewhere, unless
+			// the fingerprint is already too long for any key type
+			didKey := encodeDIDKey(tt.keyType, tt.keyBytes)
+			if len(didKey)-len(DIDKeyPrefix) > MaxFingerprintChars {
+				return
+			}
+			_, _, err = Decode(didKey)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected Decode to return %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+
+	t.Run("uncompressed error", func(t *testing.T) {
+		_, err := Encode(Bls12381G2PublicKey, g2Uncompressed[:])
+		if !errors.Is(err, ErrInvalidKeySize) {
+			t.Errorf("Expected the error to also match ErrInvalidKeySize, got %v", err)
+		}
+		if expected := "got a 192-byte uncompressed point, expected the 96-byte compressed form"; err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected error to contain %q, got %v", expected, err)
+		}
+	})
+}
+
+// keySizeSwitch is the switch-based key size lookup replaced by keyTypeMetadata,
+// kept for comparison in BenchmarkKeyTypeLookup. The map lookup is a few
+// nanoseconds slower, which is negligible next to the cost of Decode.
+func keySizeSwitch(keyType KeyType) int {
+	switch keyType {
+	case Ed25519PublicKey, X25519PublicKey:
+		return 32
+	case Secp256k1PublicKey, P256PublicKey:
+		return 33
+	case Bls12381G1PublicKey:
+		return 48
+	case Bls12381G2PublicKey:
+		return 96
+	case P384PublicKey:
+		return 49
+	default:
+		return 0
+	}
+}
+
+func BenchmarkKeyTypeLookup(b *testing.B) {
+	var payloads [][]byte
+	for _, meta := range keyTypeTable {
+		payloads = append(payloads, encodeMulticodec(meta.keyType, make([]byte, meta.size)))
+	}
+
+	// Both decode the multicodec prefix of a mix of key types and check the size
+	decode := func(b *testing.B, size func(KeyType) int) {
+		for i := 0; i < b.N; i++ {
+			payload := payloads[i%len(payloads)]
+			value, n, err := varint.FromUvarint(payload)
+			if err != nil || size(KeyType(value)) != len(payload)-n {
+				b.Fatal("unexpected key size")
+			}
+		}
+	}
+
+	b.Run("switch", func(b *testing.B) {
+		decode(b, keySizeSwitch)
+	})
+
+	b.Run("map", func(b *testing.B) {
+		decode(b, func(keyType KeyType) int {
+			size, _ := keySize(keyType)
+			return size
+		})
+	})
+}
+
+func BenchmarkDecodeMixed(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+
+	var didKeys []string
+	for _, keyType := range supportedKeyTypes {
+		didKey, err := Encode(keyType, randomValidKey(rng, keyType))
+		if err != nil {
+			b.Fatalf("Encode failed: %v", err)
+		}
+		didKeys = append(didKeys, didKey)
+	}
+
+	// Curve validation dominates EC decoding, so skip it to measure the lookups
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := Decode(didKeys[i%len(didKeys)], WithCurveValidation(false)); err != nil {
+			b.Fatalf("Decode failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package didkey
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"math/big"
+)
+
+// Verify reports whether signature is a valid signature of message by the DID key.
+// Malformed or invalid signatures report false with a nil error; an error is only
+// returned when the key type cannot be verified. Key agreement keys (X25519)
+// return ErrKeyAgreementKeyNotSignable.
+//
+// Signature formats per key type:
+//   - Ed25519: 64-byte RFC 8032 signature
+//   - secp256k1, P-256: raw r||s ECDSA signature (as in JWS ES256K/ES256) over
+//     the SHA-256 digest of message
+//   - P-384: raw r||s ECDSA signature (as in JWS ES384) over the SHA-384 digest
+//     of message
+//   - BLS12-381 G1, G2: see VerifyBLS
+//
+// The ECDSA hash can be changed with WithHash; an unsuitable hash returns
+// ErrUnsupportedHash.
+func (dk *DIDKey) Verify(message, signature []byte, opts ...Option) (bool, error) {
+	if err := requireSignatureKey(dk.keyType); err != nil {
+		return false, err
+	}
+
+	switch dk.keyType {
+	case Ed25519PublicKey:
+		return ed25519.Verify(ed25519.PublicKey(dk.keyBytes), message, signature), nil
+	case Secp256k1PublicKey:
+		x, y, ok := secp256k1Curve.decompress(dk.keyBytes)
+		if !ok {
+			return false, ErrInvalidPointWithContext(dk.keyType)
+		}
+		digest, err := ecdsaDigest(crypto.SHA256, message, opts)
+		if err != nil {
+			return false, err
+		}
+		return verifySecp256k1(&ecPoint{x: x, y: y}, digest, signature), nil
+	case P256PublicKey:
+		digest, err := ecdsaDigest(crypto.SHA256, message, opts)
+		if err != nil {
+			return false, err
+		}
+		return dk.verifyECDSA(elliptic.P256(), digest, signature)
+	case P384PublicKey:
+		digest, err := ecdsaDigest(crypto.SHA384, message, opts)
+		if err != nil {
+			return false, err
+		}
+		return dk.verifyECDSA(elliptic.P384(), digest, signature)
+	case Bls12381G1PublicKey, Bls12381G2PublicKey:
+		return dk.VerifyBLS(message, signature, opts...)
+	default:
+		return false, ErrVerificationUnsupportedWithContext(dk.keyType)
+	}
+}
+
+// ecdsaDigest hashes message for ECDSA verification with the hash set by
+// WithHash, or fallback if none is set
+func ecdsaDigest(fallback crypto.Hash, message []byte, opts []Option) ([]byte, error) {
+	hash := applyOptions(opts).hash
+	switch {
+	case hash == 0:
+		hash = fallback
+	case !hash.Available():
+		return nil, ErrUnsupportedHashWithContext(hash, "not linked into the binary")
+	case hash.Size() < sha256.Size:
+		return nil, ErrUnsupportedHashWithContext(hash, "digest shorter than 256 bits")
+	}
+
+	h := hash.New()
+	h.Write(message)
+	return h.Sum(nil), nil
+}
+
+func (dk *DIDKey) verifyECDSA(c elliptic.Curve, digest, signature []byte) (bool, error) {
+	curve, _ := curveFor(dk.keyType)
+	x, y, ok := curve.decompress(dk.keyBytes)
+	if !ok {
+		return false, ErrInvalidPointWithContext(dk.keyType)
+	}
+
+	if len(signature) != 2*curve.size {
+		return false, nil
+	}
+
+	r := new(big.Int).SetBytes(signature[:curve.size])
+	s := new(big.Int).SetBytes(signature[curve.size:])
+	return ecdsa.Verify(&ecdsa.PublicKey{Curve: c, X: x, Y: y}, digest, r, s), nil
+}
+
+// Verifier verifies signatures for a public key, for use by signing frameworks
+// that work with pre-hashed messages
+type Verifier interface {
+	// Verify returns nil if signature is a valid signature of digest, and
+	// ErrInvalidSignature otherwise
+	Verify(digest, signature []byte) error
+}
+
+// AsVerifier returns a Verifier for the DID key. As with crypto.Signer, digest
+// is the hash of the message for ECDSA keys (SHA-256 for secp256k1 and P-256,
+// SHA-384 for P-384), and the message itself for Ed25519 and BLS12-381 keys,
+// which hash internally. Signatures use the formats of Verify. Key agreement
+// keys (X25519) cannot sign and return ErrKeyAgreementKeyNotSignable.
+func (dk *DIDKey) AsVerifier(opts ...Option) (Verifier, error) {
+	if err := requireSignatureKey(dk.keyType); err != nil {
+		return nil, err
+	}
+
+	return &didKeyVerifier{dk: dk.Clone(), opts: opts}, nil
+}
+
+type didKeyVerifier struct {
+	dk   *DIDKey
+	opts []Option
+}
+
+func (v *didKeyVerifier) Verify(digest, signature []byte) error {
+	dk := v.dk
+
+	var valid bool
+	var err error
+	switch dk.keyType {
+	case Secp256k1PublicKey:
+		x, y, ok := secp256k1Curve.decompress(dk.keyBytes)
+		if !ok {
+			return ErrInvalidPointWithContext(dk.keyType)
+		}
+		valid = verifySecp256k1(&ecPoint{x: x, y: y}, digest, signature)
+	case P256PublicKey:
+		valid, err = dk.verifyECDSA(elliptic.P256(), digest, signature)
+	case P384PublicKey:
+		valid, err = dk.verifyECDSA(elliptic.P384(), digest, signature)
+	default:
+		valid, err = dk.Verify(digest, signature, v.opts...)
+	}
+
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
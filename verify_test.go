@@ -0,0 +1,265 @@
+package didkey
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestVerifyEd25519(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	dk, err := FromBytes(Ed25519PublicKey, publicKey)
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+
+	message := []byte("hello did:key")
+	signature := ed25519.Sign(privateKey, message)
+
+	if valid, err := dk.Verify(message, signature); err != nil || !valid {
+		t.Errorf("Expected valid signature, got %v, %v", valid, err)
+	}
+
+	if valid, err := dk.Verify([]byte("tampered"), signature); err != nil || valid {
+		t.Errorf("Expected invalid signature, got %v, %v", valid, err)
+	}
+}
+
+func TestVerifyECDSA(t *testing.T) {
+	message := []byte("hello did:key")
+	sha256Digest := sha256.Sum256(message)
+	sha384Digest := sha512.Sum384(message)
+
+	tests := []struct {
+		name    string
+		keyType KeyType
+		curve   elliptic.Curve
+		digest  []byte
+	}{
+		{name: "P-256", keyType: P256PublicKey, curve: elliptic.P256(), digest: sha256Digest[:]},
+		{name: "P-384", keyType: P384PublicKey, curve: elliptic.P384(), digest: sha384Digest[:]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, err := ecdsa.GenerateKey(tt.curve, rand.Reader)
+			if err != nil {
+				t.Fatalf("Failed to generate key: %v", err)
+			}
+
+			curve, _ := curveFor(tt.keyType)
+			dk, err := FromBytes(tt.keyType, curve.compress(privateKey.X, privateKey.Y))
+			if err != nil {
+				t.Fatalf("FromBytes failed: %v", err)
+			}
+
+			r, s, err := ecdsa.Sign(rand.Reader, privateKey, tt.digest)
+			if err != nil {
+				t.Fatalf("Failed to sign: %v", err)
+			}
+			signature := append(r.FillBytes(make([]byte, curve.size)), s.FillBytes(make([]byte, curve.size))...)
+
+			if valid, err := dk.Verify(message, signature); err != nil || !valid {
+				t.Errorf("Expected valid signature, got %v, %v", valid, err)
+			}
+
+			if valid, err := dk.Verify([]byte("tampered"), signature); err != nil || valid {
+				t.Errorf("Expected invalid signature, got %v, %v", valid, err)
+			}
+
+			if valid, err := dk.Verify(message, signature[:len(signature)-1]); err != nil || valid {
+				t.Errorf("Expected invalid truncated signature, got %v, %v", valid, err)
+			}
+		})
+	}
+}
+
+func TestVerifyWithHash(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	dk, err := FromBytes(P256PublicKey, p256Curve.compress(privateKey.X, privateKey.Y))
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+
+	// A P-256 signature over the SHA-512 digest, truncated to 256 bits by ECDSA
+	message := []byte("hello did:key")
+	digest := sha512.Sum512(message)
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	signature := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+
+	if valid, err := dk.Verify(message, signature, WithHash(crypto.SHA512)); err != nil || !valid {
+		t.Errorf("Expected valid SHA-512 signature, got %v, %v", valid, err)
+	}
+	if valid, err := dk.Verify([]byte("tampered"), signature, WithHash(crypto.SHA512)); err != nil || valid {
+		t.Errorf("Expected invalid signature, got %v, %v", valid, err)
+	}
+	if valid, err := dk.Verify(message, signature); err != nil || valid {
+		t.Errorf("Expected the default SHA-256 to reject the signature, got %v, %v", valid, err)
+	}
+	if valid, err := dk.Verify(message, signature, WithHash(crypto.SHA512), WithHash(0)); err != nil || valid {
+		t.Errorf("Expected a zero hash to restore SHA-256, got %v, %v", valid, err)
+	}
+
+	for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA224, crypto.BLAKE2b_256} {
+		if _, err := dk.Verify(message, signature, WithHash(hash)); !errors.Is(err, ErrUnsupportedHash) {
+			t.Errorf("%s: expected ErrUnsupportedHash, got %v", hash, err)
+		}
+	}
+
+	t.Run("Ed25519 ignores it", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		dk, err := FromBytes(Ed25519PublicKey, publicKey)
+		if err != nil {
+			t.Fatalf("FromBytes failed: %v", err)
+		}
+		if valid, err := dk.Verify(message, ed25519.Sign(privateKey, message), WithHash(crypto.SHA1)); err != nil || !valid {
+			t.Errorf("Expected valid signature, got %v, %v", valid, err)
+		}
+	})
+}
+
+func TestVerifySecp256k1(t *testing.T) {
+	keyBytes, _ := hex.DecodeString("024dc26e0d476d62f541d7b122e25fe50abe1b1b28f16b01f8c18c6b6c021b9f6e")
+	dk, err := FromBytes(Secp256k1PublicKey, keyBytes)
+	if err != nil {
+		t.Fatalf("FromBytes failed: %v", err)
+	}
+
+	message := []byte("message")
+	lowS, _ := hex.DecodeString("f01d6b9018ab421dd410404cb869072065522bf85734008f105cf385a023a80f92533e7c8c1681de5865e5b47d38c47d3e201efcf4bfe38cf9fcfcae32024524")
+	highS, _ := hex.DecodeString("f01d6b9018ab421dd410404cb869072065522bf85734008f105cf385a023a80f6dacc18373e97e21a79a1a4b82c73b817c8ebde9ba88bcaec5d561de9e33fc1d")
+
+	tests := []struct {
+		name      string
+		message   []byte
+		signature []byte
+		valid     bool
+	}{
+		{name: "low s", message: message, signature: lowS, valid: true},
+		{name: "high s", message: message, signature: highS, valid: true},
+		{name: "tampered message", message: []byte("tampered"), signature: lowS, valid: false},
+		{name: "truncated signature", message: message, signature: lowS[:63], valid: false},
+		{name: "zero signature", message: message, signature: make([]byte, 64), valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if valid, err := dk.Verify(tt.message, tt.signature); err != nil || valid != tt.valid {
+				t.Errorf("Expected %v, got %v, %v", tt.valid, valid, err)
+			}
+		})
+	}
+}
+
+func TestVerifyUnsupported(t *testing.T) {
+	dk, err := Parse("did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if _, err := dk.Verify([]byte("message"), make([]byte, 64)); !errors.Is(err, ErrKeyAgreementKeyNotSignable) {
+		t.Errorf("Expected ErrKeyAgreementKeyNotSignable, got %v", err)
+	}
+}
+
+func TestAsVerifier(t *testing.T) {
+	decode := func(s string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %v", s, err)
+		}
+		return b
+	}
+
+	// RFC 7515 appendix A.3 (ES256) and RFC 8037 appendix A.4 (EdDSA)
+	es256Input := "eyJhbGciOiJFUzI1NiJ9.eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ"
+	es256Digest := sha256.Sum256([]byte(es256Input))
+	eddsaInput := "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc"
+	secp256k1Digest := sha256.Sum256([]byte("message"))
+	secp256k1DID, err := Encode(Secp256k1PublicKey, mustDecodeHex("024dc26e0d476d62f541d7b122e25fe50abe1b1b28f16b01f8c18c6b6c021b9f6e"))
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		didKey    string
+		digest    []byte
+		signature []byte
+	}{
+		{
+			name:      "P-256",
+			didKey:    didKeyFromJWK(t, &JWK{Kty: "EC", Crv: "P-256", X: "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU", Y: "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}),
+			digest:    es256Digest[:],
+			signature: decode("DtEhU3ljbEg8L38VWAfUAqOyKAM6-Xx-F4GawxaepmXFCgfTjDxw5djxLa8ISlSApmWQxfKTUJqPP3-Kg6NU1Q"),
+		},
+		{
+			name:      "Ed25519",
+			didKey:    didKeyFromJWK(t, &JWK{Kty: "OKP", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}),
+			digest:    []byte(eddsaInput),
+			signature: decode("hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"),
+		},
+		{
+			name:      "secp256k1",
+			didKey:    secp256k1DID,
+			digest:    secp256k1Digest[:],
+			signature: mustDecodeHex("f01d6b9018ab421dd410404cb869072065522bf85734008f105cf385a023a80f92533e7c8c1681de5865e5b47d38c47d3e201efcf4bfe38cf9fcfcae32024524"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dk, err := Parse(tt.didKey)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			verifier, err := dk.AsVerifier()
+			if err != nil {
+				t.Fatalf("AsVerifier failed: %v", err)
+			}
+
+			if err := verifier.Verify(tt.digest, tt.signature); err != nil {
+				t.Errorf("Expected valid signature, got %v", err)
+			}
+
+			tampered := bytes.Clone(tt.digest)
+			tampered[0] ^= 1
+			if err := verifier.Verify(tampered, tt.signature); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+
+	t.Run("key agreement key", func(t *testing.T) {
+		dk, err := Parse("did:key:z6LSj72tK8brWgZja8NLRwPigth2T9QRiG1uH9oKZuKjdh9p")
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if _, err := dk.AsVerifier(); !errors.Is(err, ErrNotSigningKey) {
+			t.Errorf("Expected ErrNotSigningKey, got %v", err)
+		}
+	})
+}